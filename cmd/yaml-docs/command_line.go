@@ -2,10 +2,13 @@ package main
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"strings"
 
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
 	"github.com/theEndBeta/yaml-docs/pkg/document"
+	"github.com/theEndBeta/yaml-docs/pkg/util"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -23,38 +26,139 @@ func possibleLogLevels() []string {
 	return levels
 }
 
-func initializeCli() {
+// The config file provides values for any of the flags, using the flag names as keys. The default config file is
+// optional and only read if it exists.
+func readConfigFile() error {
+	configFile := viper.GetString("config")
+	if _, err := os.Stat(configFile); err != nil && !viper.IsSet("config") {
+		return nil
+	}
+
+	viper.SetConfigFile(configFile)
+	return viper.ReadInConfig()
+}
+
+func initializeCli() error {
+	if err := readConfigFile(); err != nil {
+		return &exitError{code: exitCodeUsage, err: fmt.Errorf("failed to read config file: %w", err)}
+	}
+
 	logLevelName := viper.GetString("log-level")
 	logLevel, err := log.ParseLevel(logLevelName)
 	if err != nil {
-		log.Errorf("Failed to parse provided log level %s: %s", logLevelName, err)
-		os.Exit(1)
+		return &exitError{code: exitCodeUsage, err: fmt.Errorf("failed to parse provided log level %s: %w", logLevelName, err)}
 	}
 
 	log.SetFormatter(&log.TextFormatter{FullTimestamp: true})
 	log.SetLevel(logLevel)
+
+	return nil
+}
+
+// The default template file is optional and only used if it exists. Template files that were explicitly provided are
+// expected to exist, and any missing ones are reported as warnings.
+func getTemplateFiles() []string {
+	templateFiles := viper.GetStringSlice("template-files")
+	if viper.IsSet("template-files") {
+		return templateFiles
+	}
+
+	existingTemplateFiles := make([]string, 0)
+	for _, templateFile := range templateFiles {
+		if _, err := os.Stat(templateFile); err == nil {
+			existingTemplateFiles = append(existingTemplateFiles, templateFile)
+		}
+	}
+
+	return existingTemplateFiles
 }
 
-func newYAMLDocsCommand(run func(cmd *cobra.Command, args []string)) (*cobra.Command, error) {
+// Values and template files are read from the working tree, or from a git revision when given as git:<revision>:<path>
+func getFileSystem() fs.FS {
+	return util.NewGitFileSystem(util.OSFileSystem)
+}
+
+// The output file defaults to one named for the output format, only markdown documentation goes to README.md
+func getDocumentOptions() document.Options {
+	options := document.Options{
+		FS:                getFileSystem(),
+		ValuesFiles:       viper.GetStringSlice("values-file"),
+		Profile:           viper.GetString("profile"),
+		TemplateFiles:     getTemplateFiles(),
+		SortValuesOrder:   viper.GetString("sort-values-order"),
+		OutputFormat:      viper.GetString("output-format"),
+		Columns:           viper.GetStringSlice("columns"),
+		OutputFile:        viper.GetString("output-file"),
+		ManPageName:       viper.GetString("man-page-name"),
+		EscapeFormat:      viper.GetString("escape-format"),
+		DefaultFormat:     viper.GetString("default-format"),
+		DefaultMaxLength:  viper.GetInt("default-max-length"),
+		LongDefaultStyle:  viper.GetString("long-default-style"),
+		YamlDocsVersion:   version,
+		Strict:            viper.GetBool("strict"),
+		GitHistory:        viper.GetBool("git-history"),
+		DocumentSubcharts: viper.GetBool("document-subcharts"),
+	}
+
+	if options.OutputFile == "" {
+		options.OutputFile = document.DefaultOutputFile(options)
+	}
+
+	return options
+}
+
+func newYAMLDocsCommand(run func(cmd *cobra.Command, args []string) error) (*cobra.Command, error) {
 	command := &cobra.Command{
-		Use:     "yaml-docs",
-		Short:   "yaml-docs automatically generates markdown documentation from yaml values files",
-		Version: version,
-		Run:     run,
+		Use:           "yaml-docs",
+		Short:         "yaml-docs automatically generates markdown documentation from yaml values files",
+		Version:       version,
+		RunE:          run,
+		SilenceErrors: true,
+		SilenceUsage:  true,
 	}
 
 	logLevelUsage := fmt.Sprintf("Level of logs that should printed, one of (%s)", strings.Join(possibleLogLevels(), ", "))
+	command.PersistentFlags().StringP("config", "c", ".yaml-docs.yaml", "yaml file providing values for any of the flags, keyed by flag name")
+	command.PersistentFlags().String("diagnostics-format", diagnostic.TextOutputFormat, fmt.Sprintf("format in which warnings are reported, one of (%s, %s, %s)", diagnostic.TextOutputFormat, diagnostic.JSONOutputFormat, diagnostic.SARIFOutputFormat))
+	command.PersistentFlags().String("diagnostics-file", "", "file to write json or sarif diagnostics to, stderr if empty")
 	command.PersistentFlags().BoolP("dry-run", "d", false, "don't actually render any markdown files just print to stdout passed")
+	command.PersistentFlags().Bool("document-subcharts", false, "document the values of the chart's dependencies from its charts directory, under the key of each dependency")
+	command.PersistentFlags().String("default-format", document.JSONDefaultFormat, fmt.Sprintf("format in which default values are rendered, one of (%s, %s, %s)", document.JSONDefaultFormat, document.YAMLDefaultFormat, document.FlowYAMLDefaultFormat))
+	command.PersistentFlags().Int("default-max-length", 0, "length above which defaults are moved out of the values table, 0 to only move multi-line defaults")
+	command.PersistentFlags().String("long-default-style", document.DetailsLongDefaultStyle, fmt.Sprintf("how defaults moved out of the values table are shown, one of (%s, %s)", document.DetailsLongDefaultStyle, document.FootnoteLongDefaultStyle))
+	command.PersistentFlags().String("escape-format", document.GitHubEscapeFormat, fmt.Sprintf("format that keys, defaults and descriptions are escaped for by the built-in templates and escape template functions, one of (%s, %s, %s, %s)", document.GitHubEscapeFormat, document.CommonMarkEscapeFormat, document.HTMLEscapeFormat, document.AsciiDocEscapeFormat))
+	command.PersistentFlags().Bool("git-history", false, "derive .Since and .ChangedIn of each value from the tags of the git repository containing the values file")
 	command.PersistentFlags().StringP("log-level", "l", "info", logLevelUsage)
-	command.PersistentFlags().StringP("output-file", "o", "README.md", "markdown file path relative to input template to which rendered documentation will be written")
+	command.PersistentFlags().StringP("output-file", "o", "", "file path to which rendered documentation will be written (default README.md, or values.csv, values.tsv, <man page name>.5 or values.confluence.xhtml for the other output formats)")
+	command.PersistentFlags().String("output-format", document.MarkdownOutputFormat, fmt.Sprintf("format of the rendered documentation, one of (%s, %s, %s, %s, %s)", document.MarkdownOutputFormat, document.CSVOutputFormat, document.TSVOutputFormat, document.ManOutputFormat, document.ConfluenceOutputFormat))
+	command.PersistentFlags().String("man-page-name", "", "name of the documented file in man output, defaults to the chart name or the name of the directory of the values file")
+	command.PersistentFlags().StringSlice("columns", []string{document.KeyColumn, document.TypeColumn, document.DefaultColumn, document.DescriptionColumn}, "columns to include, in order, when rendering csv or tsv output (key, type, default, description, line)")
+	command.PersistentFlags().String("profile", document.ValuesProfile, fmt.Sprintf("kind of yaml files passed as values files, one of (%s, %s, %s, %s)", document.ValuesProfile, document.CRDProfile, document.ActionProfile, document.ComposeProfile))
 	command.PersistentFlags().StringP("sort-values-order", "s", document.AlphaNumSortOrder, fmt.Sprintf("order in which to sort the values table (\"%s\" or \"%s\")", document.AlphaNumSortOrder, document.FileSortOrder))
+	command.PersistentFlags().Bool("strict", false, "fail with exit code 3 on any warning, such as unparsable comments, invalid yaml or missing template files")
 	command.PersistentFlags().StringSliceP("template-files", "t", []string{"README.md.gotmpl"}, "gotemplate file paths relative to each chart directory from which documentation will be generated")
-	command.PersistentFlags().StringSliceP("values-file", "f", []string{}, "yaml values file to be parsed into values table. Can be specified multiple times")
+	command.PersistentFlags().StringSliceP("values-file", "f", []string{}, "yaml values file to be parsed into values table, or git:<revision>:<path> to read it from a git revision. Can be specified multiple times")
+	command.PersistentFlags().BoolP("watch", "w", false, "keep running and regenerate the documentation whenever the values or template files change")
 
 	viper.AutomaticEnv()
 	viper.SetEnvPrefix("YAML_DOCS")
 	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
 	err := viper.BindPFlags(command.PersistentFlags())
+	if err != nil {
+		return command, err
+	}
+
+	serveCommand := newServeCommand()
+	coverageCommand := newCoverageCommand()
+	lintCommand := newLintCommand()
+	diffCommand := newDiffCommand()
+	command.AddCommand(serveCommand, newLSPCommand(), coverageCommand, lintCommand, diffCommand)
+
+	for _, subcommand := range []*cobra.Command{serveCommand, coverageCommand, lintCommand, diffCommand} {
+		if err := viper.BindPFlags(subcommand.Flags()); err != nil {
+			return command, err
+		}
+	}
 
-	return command, err
+	return command, nil
 }
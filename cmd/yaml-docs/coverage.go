@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+	"github.com/theEndBeta/yaml-docs/pkg/document"
+	"github.com/theEndBeta/yaml-docs/pkg/helm"
+)
+
+func newCoverageCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "coverage",
+		Short: "Report how many keys of the values files carry a description",
+		Args:  cobra.NoArgs,
+		RunE:  reportCoverage,
+	}
+
+	command.Flags().Float64("min-coverage", 0, "percentage of documented keys below which the run fails with exit code 4, 0 to never fail")
+	command.Flags().String("summary-file", "", "file to write a markdown coverage summary to, e.g. for posting on pull requests")
+
+	return command
+}
+
+func writeCoverageSummary(summaryFile string, coverages []document.Coverage, minCoverage float64) error {
+	f, err := os.Create(summaryFile)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+	return document.RenderCoverageMarkdown(f, coverages, minCoverage)
+}
+
+func reportCoverage(cmd *cobra.Command, _ []string) error {
+	if err := initializeCli(); err != nil {
+		return err
+	}
+
+	valuesFiles := viper.GetStringSlice("values-file")
+	if len(valuesFiles) == 0 {
+		return &exitError{code: exitCodeUsage, err: errors.New("as least one `values-file` must be provided")}
+	}
+
+	coverages := make([]document.Coverage, 0, len(valuesFiles))
+	warnings := make([]diagnostic.Diagnostic, 0)
+
+	// Files are parsed one at a time so that coverage is reported per file
+	for _, valuesFile := range valuesFiles {
+		valuesData, fileWarnings, err := helm.ParseValuesFS(getFileSystem(), []string{valuesFile})
+		warnings = append(warnings, fileWarnings...)
+		if err != nil {
+			return reportWarnings(warnings, &exitError{code: exitCodeFailed, err: err})
+		}
+
+		coverage, err := document.GetCoverage(valuesData, valuesFile)
+		if err != nil {
+			return &exitError{code: exitCodeFailed, err: err}
+		}
+
+		coverages = append(coverages, coverage)
+	}
+
+	if err := reportWarnings(warnings, nil); err != nil {
+		return err
+	}
+
+	if err := document.RenderCoverageText(os.Stdout, coverages); err != nil {
+		return &exitError{code: exitCodeFailed, err: err}
+	}
+
+	minCoverage := viper.GetFloat64("min-coverage")
+
+	if summaryFile := viper.GetString("summary-file"); summaryFile != "" {
+		if err := writeCoverageSummary(summaryFile, coverages, minCoverage); err != nil {
+			return &exitError{code: exitCodeFailed, err: fmt.Errorf("failed to write coverage summary: %w", err)}
+		}
+	}
+
+	if total := document.TotalCoverage(coverages); total < minCoverage {
+		return &exitError{code: exitCodeCoverage, err: fmt.Errorf("documentation coverage of %.1f%% is below the minimum of %.1f%%", total, minCoverage)}
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+
+	"github.com/theEndBeta/yaml-docs/pkg/document"
+	"github.com/theEndBeta/yaml-docs/pkg/helm"
+	"github.com/theEndBeta/yaml-docs/pkg/util"
+)
+
+const (
+	markdownDiffFormat = "markdown"
+	jsonDiffFormat     = "json"
+)
+
+// A side of the diff, either a file in the working tree or a file at a git revision
+type diffInput struct {
+	name     string
+	path     string
+	revision string
+}
+
+func newDiffCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "diff OLD_VALUES_FILE NEW_VALUES_FILE | diff --from-revision REVISION VALUES_FILE",
+		Short: "Report the documented values added, removed, retyped or with a changed default between two versions",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE:  diffValues,
+	}
+
+	command.Flags().String("from-revision", "", "git revision of the values file to compare from, instead of passing two files")
+	command.Flags().String("to-revision", "", "git revision of the values file to compare to, the working tree if empty")
+	command.Flags().String("format", markdownDiffFormat, fmt.Sprintf("format of the report, one of (%s, %s)", markdownDiffFormat, jsonDiffFormat))
+
+	return command
+}
+
+func getDiffInputs(args []string) (diffInput, diffInput, error) {
+	fromRevision := viper.GetString("from-revision")
+	toRevision := viper.GetString("to-revision")
+
+	if fromRevision == "" {
+		if len(args) != 2 || toRevision != "" {
+			return diffInput{}, diffInput{}, errors.New("either two values files or `--from-revision` and a single values file must be provided")
+		}
+
+		return diffInput{name: args[0], path: args[0]}, diffInput{name: args[1], path: args[1]}, nil
+	}
+
+	if len(args) != 1 {
+		return diffInput{}, diffInput{}, errors.New("a single values file must be provided along with `--from-revision`")
+	}
+
+	to := diffInput{name: "working tree", path: args[0], revision: toRevision}
+	if toRevision != "" {
+		to.name = toRevision
+	}
+
+	return diffInput{name: fromRevision, path: args[0], revision: fromRevision}, to, nil
+}
+
+func readDiffInput(input diffInput) (*yaml.Node, error) {
+	var contents []byte
+	var err error
+
+	if input.revision != "" {
+		contents, err = util.ReadGitFile(input.revision, input.path)
+	} else if contents, err = fs.ReadFile(getFileSystem(), input.path); err != nil {
+		err = &helm.FileError{Path: input.path, Err: err}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	valuesData, _, err := helm.ParseValuesContents(input.path, contents)
+	return valuesData, err
+}
+
+func diffValues(cmd *cobra.Command, args []string) error {
+	if err := initializeCli(); err != nil {
+		return err
+	}
+
+	from, to, err := getDiffInputs(args)
+	if err != nil {
+		return &exitError{code: exitCodeUsage, err: err}
+	}
+
+	format := viper.GetString("format")
+	if format != markdownDiffFormat && format != jsonDiffFormat {
+		return &exitError{code: exitCodeUsage, err: fmt.Errorf("unknown diff format `%s`, must be one of (%s, %s)", format, markdownDiffFormat, jsonDiffFormat)}
+	}
+
+	oldValues, err := readDiffInput(from)
+	if err != nil {
+		return &exitError{code: exitCodeFailed, err: err}
+	}
+
+	newValues, err := readDiffInput(to)
+	if err != nil {
+		return &exitError{code: exitCodeFailed, err: err}
+	}
+
+	options := getDocumentOptions()
+	diff, err := document.DiffValues(from.name, oldValues, to.name, newValues, options)
+	if err != nil {
+		return &exitError{code: exitCodeFailed, err: err}
+	}
+
+	if format == jsonDiffFormat {
+		err = document.RenderDiffJSON(os.Stdout, diff)
+	} else {
+		err = document.RenderDiffMarkdown(os.Stdout, diff, options.EscapeFormat)
+	}
+
+	if err != nil {
+		return &exitError{code: exitCodeFailed, err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+	"github.com/theEndBeta/yaml-docs/pkg/helm"
+	"github.com/theEndBeta/yaml-docs/pkg/lint"
+)
+
+func newLintCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "lint",
+		Short: "Check the comments of the values files for mistakes that keep them out of the documentation",
+		Args:  cobra.NoArgs,
+		RunE:  lintValuesFiles,
+	}
+
+	command.Flags().StringSlice("enable-rules", []string{}, fmt.Sprintf("rules to check on top of the default rules, of (%s)", strings.Join(lint.Rules(), ", ")))
+	command.Flags().StringSlice("disable-rules", []string{}, "rules not to check, takes precedence over enabled rules")
+
+	return command
+}
+
+func lintValuesFiles(cmd *cobra.Command, _ []string) error {
+	if err := initializeCli(); err != nil {
+		return err
+	}
+
+	valuesFiles := viper.GetStringSlice("values-file")
+	if len(valuesFiles) == 0 {
+		return &exitError{code: exitCodeUsage, err: errors.New("as least one `values-file` must be provided")}
+	}
+
+	config := lint.Config{
+		Enable:  viper.GetStringSlice("enable-rules"),
+		Disable: viper.GetStringSlice("disable-rules"),
+	}
+
+	if err := config.Validate(); err != nil {
+		return &exitError{code: exitCodeUsage, err: err}
+	}
+
+	options := getDocumentOptions()
+	findings := make([]diagnostic.Diagnostic, 0)
+
+	for _, valuesFile := range valuesFiles {
+		contents, err := fs.ReadFile(getFileSystem(), valuesFile)
+		if err != nil {
+			findings = append(findings, helm.DiagnosticFromError(&helm.FileError{Path: valuesFile, Err: err}, diagnostic.SeverityError))
+			continue
+		}
+
+		findings = append(findings, lint.Lint(valuesFile, contents, config, options)...)
+	}
+
+	if err := writeDiagnostics(os.Stdout, findings); err != nil {
+		return &exitError{code: exitCodeUsage, err: fmt.Errorf("failed to write diagnostics: %w", err)}
+	}
+
+	if len(findings) > 0 {
+		return &exitError{code: exitCodeLint, err: fmt.Errorf("found %d problems in %s", len(findings), strings.Join(valuesFiles, ", "))}
+	}
+
+	return nil
+}
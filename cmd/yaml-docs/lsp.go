@@ -0,0 +1,31 @@
+package main
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/theEndBeta/yaml-docs/pkg/lsp"
+)
+
+func newLSPCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lsp",
+		Short: "Run a language server for values files over stdin and stdout",
+		Args:  cobra.NoArgs,
+		RunE:  serveLanguageServer,
+	}
+}
+
+// Logs go to stderr, stdout is reserved for the protocol
+func serveLanguageServer(cmd *cobra.Command, _ []string) error {
+	if err := initializeCli(); err != nil {
+		return err
+	}
+
+	if err := lsp.NewServer(getDocumentOptions()).Serve(os.Stdin, os.Stdout); err != nil {
+		return &exitError{code: exitCodeFailed, err: err}
+	}
+
+	return nil
+}
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"strings"
 	"sync"
@@ -9,61 +12,194 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
 	"github.com/theEndBeta/yaml-docs/pkg/document"
 	"github.com/theEndBeta/yaml-docs/pkg/helm"
 )
 
-func retrieveInfoAndPrintDocumentation(valuesFiles []string, templateFiles []string, waitGroup *sync.WaitGroup, dryRun bool) {
+// Exit codes of the CLI, these are documented in the README and must stay stable for use in CI
+const (
+	exitCodeUsage          = 1
+	exitCodeFailed         = 2
+	exitCodeStrictWarnings = 3
+	exitCodeCoverage       = 4
+	exitCodeLint           = 5
+)
+
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+func getExitCode(err error) int {
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+
+	return exitCodeUsage
+}
+
+func retrieveInfoAndPrintDocumentation(options document.Options, waitGroup *sync.WaitGroup, dryRun bool, result *error) {
 	defer waitGroup.Done()
-	valuesFileInfo, err := helm.ParseValues(valuesFiles)
 
-	if err != nil {
-		log.Warnf("Error parsing information for chart %s, skipping: %s", valuesFiles, err)
+	err := document.PrintDocumentation(options, dryRun)
+	if err == nil {
+		return
+	}
+
+	var strictErr *document.StrictError
+	if errors.As(err, &strictErr) {
+		*result = &exitError{code: exitCodeStrictWarnings, err: err}
 		return
 	}
 
-	document.PrintDocumentation(valuesFileInfo, templateFiles, dryRun, version)
+	*result = &exitError{code: exitCodeFailed, err: fmt.Errorf("error generating documentation for %s: %w", options.ValuesFiles, err)}
+}
 
+// Collects diagnostics for structured output, rather than logging them as they are found
+type diagnosticsCollector struct {
+	mutex       sync.Mutex
+	diagnostics []diagnostic.Diagnostic
 }
 
-func yamlDocs(cmd *cobra.Command, _ []string) {
-	initializeCli()
+func (c *diagnosticsCollector) add(d diagnostic.Diagnostic) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 
-	var valuesFiles []string
-	valuesFiles = viper.GetStringSlice("values-file")
+	c.diagnostics = append(c.diagnostics, d)
+}
 
-	if len(valuesFiles) == 0 {
-		log.Warn("As least one `values-file` must be provided.")
-		return
+// Errors reading or parsing values files fail the run, but are still reported against the file for structured output
+func (c *diagnosticsCollector) addError(err error) {
+	var fileErr *helm.FileError
+	var parseErr *helm.ParseError
+
+	if errors.As(err, &fileErr) || errors.As(err, &parseErr) {
+		c.add(helm.DiagnosticFromError(err, diagnostic.SeverityError))
+	}
+}
+
+// Diagnostics are written to the diagnostics file if one is configured, and to defaultOutput otherwise
+func writeDiagnostics(defaultOutput io.Writer, diagnostics []diagnostic.Diagnostic) error {
+	diagnosticsFile := viper.GetString("diagnostics-file")
+	if diagnosticsFile == "" {
+		return diagnostic.Write(defaultOutput, diagnostics, viper.GetString("diagnostics-format"), version)
+	}
+
+	f, err := os.Create(diagnosticsFile)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+	return diagnostic.Write(f, diagnostics, viper.GetString("diagnostics-format"), version)
+}
+
+// Reports warnings found outside of document generation the way generation does, logging them or writing them as
+// structured diagnostics along with the error that failed the run if any, and failing in strict mode
+func reportWarnings(warnings []diagnostic.Diagnostic, runErr error) error {
+	if viper.GetString("diagnostics-format") == diagnostic.TextOutputFormat {
+		for _, warning := range warnings {
+			log.Warn(warning.String())
+		}
+	} else {
+		collector := &diagnosticsCollector{diagnostics: warnings}
+		collector.addError(runErr)
+
+		if err := writeDiagnostics(os.Stderr, collector.diagnostics); err != nil {
+			return &exitError{code: exitCodeUsage, err: fmt.Errorf("failed to write diagnostics: %w", err)}
+		}
+	}
+
+	if runErr != nil {
+		return runErr
+	}
+
+	if viper.GetBool("strict") && len(warnings) > 0 {
+		return &exitError{code: exitCodeStrictWarnings, err: &document.StrictError{Warnings: warnings}}
 	}
 
-	templateFiles := viper.GetStringSlice("template-files")
-	log.Debugf("Rendering from optional template files [%s]", strings.Join(templateFiles, ", "))
+	return nil
+}
+
+// Renders the documentation once with the current flags, writing any structured diagnostics
+func generateDocumentation() error {
+	options := getDocumentOptions()
+
+	if len(options.ValuesFiles) == 0 {
+		return &exitError{code: exitCodeUsage, err: errors.New("as least one `values-file` must be provided")}
+	}
+
+	log.Debugf("Rendering from optional template files [%s]", strings.Join(options.TemplateFiles, ", "))
+
+	diagnosticsFormat := viper.GetString("diagnostics-format")
+	collector := &diagnosticsCollector{diagnostics: make([]diagnostic.Diagnostic, 0)}
+	if diagnosticsFormat != diagnostic.TextOutputFormat {
+		options.OnWarning = collector.add
+	}
 
 	dryRun := viper.GetBool("dry-run")
 	waitGroup := sync.WaitGroup{}
+	var result error
 
 	waitGroup.Add(1)
 
 	// On dry runs all output goes to stdout, and so as to not jumble things, generate serially
 	if dryRun {
-		retrieveInfoAndPrintDocumentation(valuesFiles, templateFiles, &waitGroup, dryRun)
+		retrieveInfoAndPrintDocumentation(options, &waitGroup, dryRun, &result)
 	} else {
-		go retrieveInfoAndPrintDocumentation(valuesFiles, templateFiles, &waitGroup, dryRun)
+		go retrieveInfoAndPrintDocumentation(options, &waitGroup, dryRun, &result)
 	}
 
 	waitGroup.Wait()
+
+	if diagnosticsFormat != diagnostic.TextOutputFormat {
+		collector.addError(result)
+
+		if err := writeDiagnostics(os.Stderr, collector.diagnostics); err != nil {
+			return &exitError{code: exitCodeUsage, err: fmt.Errorf("failed to write diagnostics: %w", err)}
+		}
+	}
+
+	return result
+}
+
+func yamlDocs(cmd *cobra.Command, _ []string) error {
+	if err := initializeCli(); err != nil {
+		return err
+	}
+
+	err := generateDocumentation()
+	if !viper.GetBool("watch") || err != nil && getExitCode(err) == exitCodeUsage {
+		return err
+	}
+
+	// Keep watching after a failed render, the next change may well fix it
+	if err != nil {
+		log.Errorf("%s", err)
+	}
+
+	return watchDocumentation()
 }
 
 func main() {
 	command, err := newYAMLDocsCommand(yamlDocs)
 	if err != nil {
 		log.Errorf("Failed to create the CLI commander: %s", err)
-		os.Exit(1)
+		os.Exit(exitCodeUsage)
 	}
 
 	if err := command.Execute(); err != nil {
-		log.Errorf("Failed to start the CLI: %s", err)
-		os.Exit(1)
+		log.Errorf("%s", err)
+		os.Exit(getExitCode(err))
 	}
 }
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/theEndBeta/yaml-docs/pkg/document"
+	"github.com/theEndBeta/yaml-docs/pkg/preview"
+	"github.com/theEndBeta/yaml-docs/pkg/util"
+)
+
+func newServeCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve a live preview of the documentation rendered to HTML, reloading it when the inputs change",
+		Args:  cobra.NoArgs,
+		RunE:  serveDocumentation,
+	}
+
+	command.Flags().String("address", "localhost:8080", "address on which the preview is served")
+
+	return command
+}
+
+// The preview always renders markdown, whichever output format is configured, as that's what is shown as HTML
+func renderPreview() ([]byte, error) {
+	options := getDocumentOptions()
+	options.OutputFormat = document.MarkdownOutputFormat
+
+	return document.Generate(options)
+}
+
+func serveDocumentation(cmd *cobra.Command, _ []string) error {
+	if err := initializeCli(); err != nil {
+		return err
+	}
+
+	if len(viper.GetStringSlice("values-file")) == 0 {
+		return &exitError{code: exitCodeUsage, err: errors.New("as least one `values-file` must be provided")}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	previewServer := preview.NewServer(getDocumentOptions().OutputFile, os.DirFS("."), renderPreview)
+	httpServer := &http.Server{Addr: viper.GetString("address"), Handler: previewServer}
+
+	go func() {
+		err := util.WatchFiles(ctx, getWatchedFiles(), watchDebounce, func(changed []string) {
+			log.Infof("%s changed, reloading preview", strings.Join(changed, ", "))
+			previewServer.Reload()
+		})
+
+		if err != nil {
+			log.Errorf("Failed to watch for changes, the preview won't reload: %s", err)
+		}
+	}()
+
+	// Open pages keep their event streams open, so close the server rather than waiting for them to finish
+	go func() {
+		<-ctx.Done()
+		httpServer.Close()
+	}()
+
+	log.Infof("Serving documentation preview on http://%s", httpServer.Addr)
+
+	if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return &exitError{code: exitCodeFailed, err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/theEndBeta/yaml-docs/pkg/helm"
+	"github.com/theEndBeta/yaml-docs/pkg/util"
+)
+
+// How long to wait for further changes before regenerating, editors often write a file several times when saving
+const watchDebounce = 200 * time.Millisecond
+
+// The values files and every configured template file are watched, including the default template, so that creating it
+// switches away from the built-in template. The output file is never watched, as writing it would trigger another run,
+// and neither are files read from git revisions, which don't change.
+func getWatchedFiles() []string {
+	outputFile, _ := filepath.Abs(getDocumentOptions().OutputFile)
+	watchedFiles := make([]string, 0)
+
+	files := append(viper.GetStringSlice("values-file"), viper.GetStringSlice("template-files")...)
+	if valuesFiles := viper.GetStringSlice("values-file"); len(valuesFiles) > 0 {
+		files = append(files, helm.ChartFilePaths(valuesFiles[0])...)
+	}
+
+	for _, file := range files {
+		if absolutePath, _ := filepath.Abs(file); absolutePath == outputFile {
+			continue
+		}
+
+		if _, _, isGitPath := util.ParseGitPath(file); isGitPath {
+			continue
+		}
+
+		watchedFiles = append(watchedFiles, file)
+	}
+
+	return watchedFiles
+}
+
+func getOutputDescription() string {
+	if viper.GetBool("dry-run") {
+		return "stdout"
+	}
+
+	return getDocumentOptions().OutputFile
+}
+
+// Regenerates the documentation whenever one of the watched files changes, until interrupted
+func watchDocumentation() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	watchedFiles := getWatchedFiles()
+	log.Infof("Watching %s for changes", strings.Join(watchedFiles, ", "))
+
+	return util.WatchFiles(ctx, watchedFiles, watchDebounce, func(changed []string) {
+		start := time.Now()
+		err := generateDocumentation()
+		timestamp := start.Format("15:04:05")
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %s changed, failed to regenerate %s: %s\n", timestamp, strings.Join(changed, ", "), getOutputDescription(), err)
+			return
+		}
+
+		fmt.Fprintf(os.Stderr, "%s %s changed, regenerated %s in %s\n", timestamp, strings.Join(changed, ", "), getOutputDescription(), time.Since(start).Round(time.Millisecond))
+	})
+}
@@ -0,0 +1,59 @@
+package diagnostic
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+var yamlErrorLineRegex = regexp.MustCompile(`line (\d+)`)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityNote    = "note"
+)
+
+// Diagnostic is a problem found in an input file, reported against the rule it violates
+type Diagnostic struct {
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	location := d.File
+
+	if d.Line > 0 {
+		location = fmt.Sprintf("%s:%d", location, d.Line)
+	}
+
+	if d.Column > 0 {
+		location = fmt.Sprintf("%s:%d", location, d.Column)
+	}
+
+	message := d.Message
+	if d.Rule != "" {
+		message = fmt.Sprintf("%s [%s]", message, d.Rule)
+	}
+
+	if location == "" {
+		return message
+	}
+
+	return fmt.Sprintf("%s: %s", location, message)
+}
+
+// LineFromYamlError extracts the line number from the errors of the yaml library, or returns 0 if there is none
+func LineFromYamlError(err error) int {
+	match := yamlErrorLineRegex.FindStringSubmatch(err.Error())
+	if len(match) < 2 {
+		return 0
+	}
+
+	line, _ := strconv.Atoi(match[1])
+	return line
+}
@@ -0,0 +1,178 @@
+package diagnostic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	TextOutputFormat  = "text"
+	JSONOutputFormat  = "json"
+	SARIFOutputFormat = "sarif"
+)
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	ShortDescription *sarifMessage `json:"shortDescription,omitempty"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+func getSarifRules(diagnostics []Diagnostic) []sarifRule {
+	ruleIDs := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, d := range diagnostics {
+		if !seen[d.Rule] {
+			seen[d.Rule] = true
+			ruleIDs = append(ruleIDs, d.Rule)
+		}
+	}
+
+	sort.Strings(ruleIDs)
+	rules := make([]sarifRule, 0, len(ruleIDs))
+
+	for _, id := range ruleIDs {
+		rule := sarifRule{ID: id}
+		if description := RuleDescription(id); description != "" {
+			rule.ShortDescription = &sarifMessage{Text: description}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+func getSarifResult(d Diagnostic) sarifResult {
+	result := sarifResult{
+		RuleID:  d.Rule,
+		Level:   d.Severity,
+		Message: sarifMessage{Text: d.Message},
+	}
+
+	if d.File == "" {
+		return result
+	}
+
+	location := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(d.File)}}
+	if d.Line > 0 {
+		location.Region = &sarifRegion{StartLine: d.Line, StartColumn: d.Column}
+	}
+
+	result.Locations = []sarifLocation{{PhysicalLocation: location}}
+	return result
+}
+
+// WriteSARIF writes diagnostics as a SARIF 2.1.0 log, as understood by e.g. GitHub code scanning
+func WriteSARIF(output io.Writer, diagnostics []Diagnostic, toolVersion string) error {
+	results := make([]sarifResult, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		results = append(results, getSarifResult(d))
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "yaml-docs",
+				Version:        toolVersion,
+				InformationURI: "https://github.com/theEndBeta/yaml-docs",
+				Rules:          getSarifRules(diagnostics),
+			}},
+			Results: results,
+		}},
+	}
+
+	encoder := json.NewEncoder(output)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
+
+// WriteJSON writes diagnostics as a json list
+func WriteJSON(output io.Writer, diagnostics []Diagnostic) error {
+	if diagnostics == nil {
+		diagnostics = make([]Diagnostic, 0)
+	}
+
+	encoder := json.NewEncoder(output)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(diagnostics)
+}
+
+// Write writes diagnostics in one of the diagnostic output formats
+func Write(output io.Writer, diagnostics []Diagnostic, format string, toolVersion string) error {
+	switch format {
+	case JSONOutputFormat:
+		return WriteJSON(output, diagnostics)
+	case SARIFOutputFormat:
+		return WriteSARIF(output, diagnostics, toolVersion)
+	case TextOutputFormat, "":
+		for _, d := range diagnostics {
+			if _, err := fmt.Fprintln(output, d.String()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("unknown diagnostics format `%s`, must be one of (%s, %s, %s)", format, TextOutputFormat, JSONOutputFormat, SARIFOutputFormat)
+}
@@ -0,0 +1,80 @@
+package diagnostic
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testDiagnostics = []Diagnostic{
+	{File: "charts/app/values.yaml", Line: 4, Column: 3, Rule: MalformedDefaultRule, Severity: SeverityWarning, Message: "malformed"},
+	{File: "README.md.gotmpl", Rule: MissingTemplateRule, Severity: SeverityWarning, Message: "missing"},
+}
+
+func TestWriteJSON(t *testing.T) {
+	var output bytes.Buffer
+	require.NoError(t, Write(&output, testDiagnostics, JSONOutputFormat, "1.0.0"))
+
+	var decoded []Diagnostic
+	require.NoError(t, json.Unmarshal(output.Bytes(), &decoded))
+	assert.Equal(t, testDiagnostics, decoded)
+}
+
+func TestWriteJSONEmpty(t *testing.T) {
+	var output bytes.Buffer
+	require.NoError(t, WriteJSON(&output, nil))
+	assert.Equal(t, "[]\n", output.String())
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var output bytes.Buffer
+	require.NoError(t, Write(&output, testDiagnostics, SARIFOutputFormat, "1.0.0"))
+
+	var decoded sarifLog
+	require.NoError(t, json.Unmarshal(output.Bytes(), &decoded))
+
+	require.Len(t, decoded.Runs, 1)
+	run := decoded.Runs[0]
+
+	assert.Equal(t, "1.0.0", run.Tool.Driver.Version)
+	assert.Equal(t, []sarifRule{
+		{ID: MalformedDefaultRule, ShortDescription: &sarifMessage{Text: RuleDescription(MalformedDefaultRule)}},
+		{ID: MissingTemplateRule, ShortDescription: &sarifMessage{Text: RuleDescription(MissingTemplateRule)}},
+	}, run.Tool.Driver.Rules)
+
+	require.Len(t, run.Results, 2)
+	assert.Equal(t, MalformedDefaultRule, run.Results[0].RuleID)
+	assert.Equal(t, SeverityWarning, run.Results[0].Level)
+	assert.Equal(t, "charts/app/values.yaml", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, &sarifRegion{StartLine: 4, StartColumn: 3}, run.Results[0].Locations[0].PhysicalLocation.Region)
+	assert.Nil(t, run.Results[1].Locations[0].PhysicalLocation.Region)
+}
+
+func TestWriteSARIFError(t *testing.T) {
+	var output bytes.Buffer
+	require.NoError(t, Write(&output, []Diagnostic{{Rule: ErrorRule, Severity: SeverityError, Message: "failed"}}, SARIFOutputFormat, "1.0.0"))
+
+	var decoded sarifLog
+	require.NoError(t, json.Unmarshal(output.Bytes(), &decoded))
+
+	require.Len(t, decoded.Runs, 1)
+	assert.Equal(t, []sarifRule{{ID: ErrorRule, ShortDescription: &sarifMessage{Text: RuleDescription(ErrorRule)}}}, decoded.Runs[0].Tool.Driver.Rules)
+	assert.Equal(t, ErrorRule, decoded.Runs[0].Results[0].RuleID)
+}
+
+func TestWriteText(t *testing.T) {
+	var output bytes.Buffer
+	require.NoError(t, Write(&output, testDiagnostics, TextOutputFormat, "1.0.0"))
+
+	const expected = "charts/app/values.yaml:4:3: malformed [malformed-default-comment]\n" +
+		"README.md.gotmpl: missing [missing-template]\n"
+	assert.Equal(t, expected, output.String())
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	var output bytes.Buffer
+	assert.Error(t, Write(&output, testDiagnostics, "xml", "1.0.0"))
+}
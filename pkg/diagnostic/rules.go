@@ -0,0 +1,51 @@
+package diagnostic
+
+// Identifiers of the rules diagnostics are reported against
+const (
+	UnreadableValuesFileRule      = "unreadable-values-file"
+	InvalidYamlRule               = "invalid-yaml"
+	IgnoredKeyCommentRule         = "ignored-key-comment"
+	MalformedDefaultRule          = "malformed-default-comment"
+	DefaultWithoutDescriptionRule = "default-without-description"
+	MissingTemplateRule           = "missing-template"
+	UndocumentedKeyRule           = "undocumented-key"
+	DefaultBeforeDescriptionRule  = "default-before-description"
+	UnknownKeyCommentRule         = "unknown-key-comment"
+	DetachedDescriptionRule       = "detached-description"
+	DescriptionStyleRule          = "description-style"
+	DuplicateKeyRule              = "duplicate-key"
+	InvalidChartMetadataRule      = "invalid-chart-metadata"
+	MissingSubchartRule           = "missing-subchart"
+	UnusedValueRule               = "unused-value"
+	UndefinedValueRule            = "undefined-value"
+	InvalidChartTemplateRule      = "invalid-chart-template"
+	GitHistoryRule                = "git-history"
+	ErrorRule                     = "error"
+)
+
+var ruleDescriptions = map[string]string{
+	UnreadableValuesFileRule:      "Values file could not be read",
+	InvalidYamlRule:               "Values file is not valid yaml",
+	IgnoredKeyCommentRule:         "Description comment naming its key directly above the key is ignored",
+	MalformedDefaultRule:          "@default comment does not match `# @default -- <default>`",
+	DefaultWithoutDescriptionRule: "@default comment without a preceding `# --` description is ignored",
+	MissingTemplateRule:           "Template file does not exist",
+	UndocumentedKeyRule:           "Key is documented without a description",
+	DefaultBeforeDescriptionRule:  "@default comment placed before the `# --` description is ignored",
+	UnknownKeyCommentRule:         "Description comment naming its key refers to a key that doesn't exist",
+	DetachedDescriptionRule:       "`# --` description separated from its key by a blank line is ignored",
+	DescriptionStyleRule:          "Description doesn't start with a capital letter or end with punctuation",
+	DuplicateKeyRule:              "Key is defined more than once in the same object",
+	InvalidChartMetadataRule:      "Chart.yaml, or the lock or requirements file next to it, could not be read or parsed",
+	MissingSubchartRule:           "Dependency of the chart was not found in its charts directory",
+	UnusedValueRule:               "Documented value is not used by any template of the chart",
+	UndefinedValueRule:            "Template of the chart uses a value that is not in the values file",
+	InvalidChartTemplateRule:      "Template of the chart could not be parsed, its uses of values aren't checked",
+	GitHistoryRule:                "Git history of the values file could not be read",
+	ErrorRule:                     "Error not covered by any other rule, such as a documentation template that fails to render",
+}
+
+// RuleDescription returns a short description of a rule, or an empty string for unknown rules
+func RuleDescription(rule string) string {
+	return ruleDescriptions[rule]
+}
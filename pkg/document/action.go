@@ -0,0 +1,145 @@
+package document
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+	"github.com/theEndBeta/yaml-docs/pkg/helm"
+)
+
+// Placeholder for the repository and version of the action in the usage snippet, templates may redefine action.uses
+const actionUsesPlaceholder = "OWNER/REPO@VERSION"
+
+// ActionMetadata is a GitHub action.yml documented with ActionProfile. Its inputs are also the rows of the values table.
+type ActionMetadata struct {
+	Name        string
+	Description string
+	Author      string
+	Inputs      []ValueRow
+	Outputs     []ValueRow
+}
+
+type actionFile struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Author      string `yaml:"author"`
+}
+
+// Required is a string, as actions are free to write it as `'true'` as well as `true`
+type actionInput struct {
+	Description        string `yaml:"description"`
+	Required           string `yaml:"required"`
+	DeprecationMessage string `yaml:"deprecationMessage"`
+}
+
+type actionOutput struct {
+	Description string `yaml:"description"`
+}
+
+// Inputs and outputs are documented by their description fields rather than by comments. Defaults of inputs are
+// strings passed to the action as is, and rendered in code spans without any other formatting.
+func createActionRows(parameters *yaml.Node, isInput bool, options Options) ([]ValueRow, error) {
+	rows := make([]ValueRow, 0)
+	if parameters == nil || parameters.Kind != yaml.MappingNode {
+		return rows, nil
+	}
+
+	for i := 0; i+1 < len(parameters.Content); i += 2 {
+		keyNode := parameters.Content[i]
+		valueNode := parameters.Content[i+1]
+		row := ValueRow{Key: keyNode.Value, Column: keyNode.Column, LineNumber: keyNode.Line}
+
+		if !isInput {
+			var output actionOutput
+			if err := valueNode.Decode(&output); err != nil {
+				return nil, fmt.Errorf("invalid output %s: %w", keyNode.Value, err)
+			}
+
+			row.Description = strings.TrimSpace(output.Description)
+			rows = append(rows, row)
+			continue
+		}
+
+		var input actionInput
+		if err := valueNode.Decode(&input); err != nil {
+			return nil, fmt.Errorf("invalid input %s: %w", keyNode.Value, err)
+		}
+
+		row.Type = stringType
+		row.Description = strings.TrimSpace(input.Description)
+		row.Required = input.Required == "true"
+		row.DeprecationMessage = strings.TrimSpace(input.DeprecationMessage)
+
+		// An empty default passes the same empty string as no default at all
+		if defaultNode := lookupNode(valueNode, "default"); defaultNode != nil && defaultNode.Value != "" {
+			row.Default = fmt.Sprintf("`%s`", defaultNode.Value)
+		}
+
+		rows = append(rows, row)
+	}
+
+	sortValueRows(rows, options.SortValuesOrder)
+
+	return rows, nil
+}
+
+func getActionMetadata(file string, options Options) (ActionMetadata, []diagnostic.Diagnostic, error) {
+	values, warnings, err := helm.ParseValuesFS(options.FileSystem(), []string{file})
+	if err != nil {
+		return ActionMetadata{}, nil, err
+	}
+
+	var action actionFile
+	if err := values.Decode(&action); err != nil {
+		return ActionMetadata{}, nil, &helm.ParseError{Path: file, Line: diagnostic.LineFromYamlError(err), Err: err}
+	}
+
+	inputs, err := createActionRows(lookupNode(values, "inputs"), true, options)
+	if err != nil {
+		return ActionMetadata{}, nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	outputs, err := createActionRows(lookupNode(values, "outputs"), false, options)
+	if err != nil {
+		return ActionMetadata{}, nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	return ActionMetadata{
+		Name:        action.Name,
+		Description: strings.TrimSpace(action.Description),
+		Author:      action.Author,
+		Inputs:      inputs,
+		Outputs:     outputs,
+	}, warnings, nil
+}
+
+// Only the first file is documented, an action is defined by a single action.yml
+func generateAction(options Options) ([]byte, []diagnostic.Diagnostic, error) {
+	if len(options.ValuesFiles) == 0 {
+		return nil, nil, errors.New("no action file given")
+	}
+
+	action, warnings, err := getActionMetadata(options.ValuesFiles[0], options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	templateData := TemplateData{
+		YamlDocsVersion:  options.YamlDocsVersion,
+		Values:           action.Inputs,
+		ValuesTree:       make([]ValueTreeNode, 0),
+		LongDefaultStyle: options.LongDefaultStyle,
+		Action:           action,
+	}
+
+	output, formatWarnings, err := renderTemplateData(templateData, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return output, append(warnings, formatWarnings...), nil
+}
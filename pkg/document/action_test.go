@@ -0,0 +1,125 @@
+package document
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const widgetAction = `name: Setup widget
+description: Installs the widget CLI.
+author: Example
+inputs:
+  version:
+    description: Version of the widget CLI to install.
+    required: true
+  token:
+    description: |-
+      Token used to download releases.
+      Defaults to the workflow token.
+    required: 'false'
+    default: ${{ github.token }}
+  legacy-mode:
+    description: Use the old installer.
+    default: ''
+    deprecationMessage: The old installer is no longer maintained.
+outputs:
+  path:
+    description: Path of the installed CLI.
+runs:
+  using: node20
+  main: dist/index.js
+`
+
+func TestGetActionMetadata(t *testing.T) {
+	fsys := fstest.MapFS{
+		"action.yml": {Data: []byte(widgetAction)},
+	}
+
+	action, warnings, err := getActionMetadata("action.yml", Options{FS: fsys, SortValuesOrder: FileSortOrder})
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+
+	assert.Equal(t, "Setup widget", action.Name)
+	assert.Equal(t, "Installs the widget CLI.", action.Description)
+	assert.Equal(t, "Example", action.Author)
+	assert.Equal(t, []ValueRow{
+		{Key: "version", Type: "string", Description: "Version of the widget CLI to install.", Required: true, Column: 3, LineNumber: 5},
+		{Key: "token", Type: "string", Default: "`${{ github.token }}`", Description: "Token used to download releases.\nDefaults to the workflow token.", Column: 3, LineNumber: 8},
+		{Key: "legacy-mode", Type: "string", Description: "Use the old installer.", DeprecationMessage: "The old installer is no longer maintained.", Column: 3, LineNumber: 14},
+	}, action.Inputs)
+	assert.Equal(t, []ValueRow{
+		{Key: "path", Description: "Path of the installed CLI.", Column: 3, LineNumber: 19},
+	}, action.Outputs)
+}
+
+func TestGenerateAction(t *testing.T) {
+	fsys := fstest.MapFS{
+		"action.yml": {Data: []byte(widgetAction)},
+	}
+
+	output, err := Generate(Options{FS: fsys, ValuesFiles: []string{"action.yml"}, Profile: ActionProfile, SortValuesOrder: FileSortOrder})
+	require.NoError(t, err)
+
+	assert.Equal(t, "# Setup widget\n\n"+
+		"Installs the widget CLI.\n\n"+
+		"## Inputs\n\n"+
+		"| Input | Required | Default | Description |\n"+
+		"|-------|----------|---------|-------------|\n"+
+		"| version | yes |  | Version of the widget CLI to install. |\n"+
+		"| token | no | `${{ github.token }}` | Token used to download releases.<br>Defaults to the workflow token. |\n"+
+		"| legacy-mode | no |  | **Deprecated:** The old installer is no longer maintained. Use the old installer. |\n\n"+
+		"## Outputs\n\n"+
+		"| Output | Description |\n"+
+		"|--------|-------------|\n"+
+		"| path | Path of the installed CLI. |\n\n"+
+		"## Usage\n\n"+
+		"```yaml\n"+
+		"- uses: OWNER/REPO@VERSION\n"+
+		"  with:\n"+
+		"    # Version of the widget CLI to install.\n"+
+		"    # Required.\n"+
+		"    version: ''\n"+
+		"    # Token used to download releases.\n"+
+		"    # Defaults to the workflow token.\n"+
+		"    token: '${{ github.token }}'\n"+
+		"    # Use the old installer.\n"+
+		"    legacy-mode: ''\n"+
+		"```\n\n", string(output))
+
+	output, err = Generate(Options{FS: fsys, ValuesFiles: []string{"action.yml"}, Profile: ActionProfile, OutputFormat: CSVOutputFormat, Columns: []string{KeyColumn, DefaultColumn}})
+	require.NoError(t, err)
+	assert.Equal(t, "key,default\nlegacy-mode,\ntoken,${{ github.token }}\nversion,\n", string(output))
+}
+
+func TestActionUsageDefaults(t *testing.T) {
+	fsys := fstest.MapFS{
+		"action.yml": {Data: []byte(`name: Greet
+inputs:
+  greeting:
+    default: "it's me"
+  script:
+    default: |
+      echo 'hello'
+      echo done
+  quoted:
+    default: '` + "`code`" + `'
+`)},
+		"README.md.gotmpl": {Data: []byte(`{{ template "action.usage" . }}`)},
+	}
+
+	output, err := Generate(Options{FS: fsys, ValuesFiles: []string{"action.yml"}, TemplateFiles: []string{"README.md.gotmpl"}, Profile: ActionProfile, SortValuesOrder: FileSortOrder})
+	require.NoError(t, err)
+
+	assert.Equal(t, "```yaml\n"+
+		"- uses: OWNER/REPO@VERSION\n"+
+		"  with:\n"+
+		"    greeting: 'it''s me'\n"+
+		"    script: |\n"+
+		"      echo 'hello'\n"+
+		"      echo done\n"+
+		"    quoted: '`code`'\n"+
+		"```", string(output))
+}
@@ -0,0 +1,37 @@
+package document
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+	"github.com/theEndBeta/yaml-docs/pkg/helm"
+)
+
+// Chart.yaml is optional, values files that aren't part of a chart are documented without chart metadata. An invalid
+// Chart.yaml is reported as a warning, and the documentation rendered as if it were missing. An invalid lock or
+// requirements file only loses the dependencies it would have added.
+func getChartMetadata(options Options) (helm.ChartMetadata, []diagnostic.Diagnostic) {
+	if len(options.ValuesFiles) == 0 {
+		return helm.ChartMetadata{}, nil
+	}
+
+	chartPath := helm.ChartFilePath(options.ValuesFiles[0])
+	chart, warnings, err := helm.ParseChartMetadata(options.FileSystem(), chartPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return helm.ChartMetadata{}, nil
+	}
+
+	if err != nil {
+		return helm.ChartMetadata{}, []diagnostic.Diagnostic{{
+			File:     chartPath,
+			Line:     diagnostic.LineFromYamlError(err),
+			Rule:     diagnostic.InvalidChartMetadataRule,
+			Severity: diagnostic.SeverityWarning,
+			Message:  fmt.Sprintf("invalid chart metadata, rendering without it: %s", err),
+		}}
+	}
+
+	return chart, warnings
+}
@@ -0,0 +1,387 @@
+package document
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+	"github.com/theEndBeta/yaml-docs/pkg/helm"
+)
+
+// ComposeService is a service of a compose file documented with ComposeProfile. Its environment variables are value
+// rows, with the value a variable is set to as the default.
+type ComposeService struct {
+	Name string
+
+	// Description from the `# --` comment of the service
+	Description string
+	Image       string
+
+	// Build context of services built from a Dockerfile
+	Build            string
+	Ports            []ComposePort
+	Volumes          []ComposeVolume
+	DependsOn        []ComposeDependency
+	Values           []ValueRow
+	LongDefaultStyle string
+}
+
+// ComposePort is a port of a ComposeService, Published is empty for ports that are only exposed to other services
+type ComposePort struct {
+	HostIP    string
+	Published string
+	Target    string
+	Protocol  string
+}
+
+// ComposeVolume is a volume or bind mount of a ComposeService, Source is empty for anonymous volumes
+type ComposeVolume struct {
+	Source string
+	Target string
+	Mode   string
+}
+
+// ComposeDependency is a service that a ComposeService depends on, and the condition it waits for if any
+type ComposeDependency struct {
+	Name      string
+	Condition string
+}
+
+type composeService struct {
+	Image string `yaml:"image"`
+}
+
+type composePort struct {
+	HostIP    string `yaml:"host_ip"`
+	Published string `yaml:"published"`
+	Target    string `yaml:"target"`
+	Protocol  string `yaml:"protocol"`
+}
+
+type composeVolume struct {
+	Source   string `yaml:"source"`
+	Target   string `yaml:"target"`
+	ReadOnly bool   `yaml:"read_only"`
+}
+
+// Short port syntax is [[HOST_IP:]PUBLISHED:]TARGET[/PROTOCOL]
+func parseComposePort(value string) ComposePort {
+	var port ComposePort
+	if i := strings.LastIndex(value, "/"); i >= 0 {
+		port.Protocol = value[i+1:]
+		value = value[:i]
+	}
+
+	i := strings.LastIndex(value, ":")
+	if i < 0 {
+		port.Target = value
+		return port
+	}
+
+	port.Target = value[i+1:]
+	port.Published = value[:i]
+	if i := strings.LastIndex(port.Published, ":"); i >= 0 {
+		port.HostIP = port.Published[:i]
+		port.Published = port.Published[i+1:]
+	}
+
+	return port
+}
+
+func getComposePorts(node *yaml.Node) ([]ComposePort, error) {
+	ports := make([]ComposePort, 0)
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return ports, nil
+	}
+
+	for _, portNode := range node.Content {
+		if portNode.Kind == yaml.ScalarNode {
+			ports = append(ports, parseComposePort(portNode.Value))
+			continue
+		}
+
+		var port composePort
+		if err := portNode.Decode(&port); err != nil {
+			return nil, fmt.Errorf("invalid port: %w", err)
+		}
+
+		ports = append(ports, ComposePort(port))
+	}
+
+	return ports, nil
+}
+
+// Short volume syntax is [SOURCE:]TARGET[:MODE]
+func parseComposeVolume(value string) ComposeVolume {
+	parts := strings.SplitN(value, ":", 3)
+	switch len(parts) {
+	case 1:
+		return ComposeVolume{Target: parts[0]}
+	case 2:
+		return ComposeVolume{Source: parts[0], Target: parts[1]}
+	}
+
+	return ComposeVolume{Source: parts[0], Target: parts[1], Mode: parts[2]}
+}
+
+func getComposeVolumes(node *yaml.Node) ([]ComposeVolume, error) {
+	volumes := make([]ComposeVolume, 0)
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return volumes, nil
+	}
+
+	for _, volumeNode := range node.Content {
+		if volumeNode.Kind == yaml.ScalarNode {
+			volumes = append(volumes, parseComposeVolume(volumeNode.Value))
+			continue
+		}
+
+		var volume composeVolume
+		if err := volumeNode.Decode(&volume); err != nil {
+			return nil, fmt.Errorf("invalid volume: %w", err)
+		}
+
+		mode := ""
+		if volume.ReadOnly {
+			mode = "ro"
+		}
+
+		volumes = append(volumes, ComposeVolume{Source: volume.Source, Target: volume.Target, Mode: mode})
+	}
+
+	return volumes, nil
+}
+
+// Dependencies are either a list of services, or a mapping of services to the condition they are waited for with
+func getComposeDependencies(node *yaml.Node) []ComposeDependency {
+	dependencies := make([]ComposeDependency, 0)
+	if node == nil {
+		return dependencies
+	}
+
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, dependencyNode := range node.Content {
+			dependencies = append(dependencies, ComposeDependency{Name: dependencyNode.Value})
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			dependency := ComposeDependency{Name: node.Content[i].Value}
+			if conditionNode := lookupNode(node.Content[i+1], "condition"); conditionNode != nil {
+				dependency.Condition = conditionNode.Value
+			}
+
+			dependencies = append(dependencies, dependency)
+		}
+	}
+
+	return dependencies
+}
+
+// Tag of the `<<` keys merging the keys of other mappings into a mapping
+const mergeTag = "!!merge"
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	return node
+}
+
+// Resolves aliases, and replaces the merge keys of a mapping with the keys they merge in. Keys of the mapping itself
+// take precedence over merged keys, and of a list of merged mappings the first one to have a key wins.
+func expandMergeKeys(node *yaml.Node) *yaml.Node {
+	node = resolveAlias(node)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return node
+	}
+
+	ownKeys := make(map[string]bool)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Tag != mergeTag {
+			ownKeys[node.Content[i].Value] = true
+		}
+	}
+
+	expanded := *node
+	expanded.Content = make([]*yaml.Node, 0, len(node.Content))
+	mergedKeys := make(map[string]bool)
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Tag != mergeTag {
+			expanded.Content = append(expanded.Content, node.Content[i], node.Content[i+1])
+			continue
+		}
+
+		merged := []*yaml.Node{node.Content[i+1]}
+		if value := resolveAlias(node.Content[i+1]); value.Kind == yaml.SequenceNode {
+			merged = value.Content
+		}
+
+		for _, mergedNode := range merged {
+			mergedNode = expandMergeKeys(mergedNode)
+			if mergedNode == nil || mergedNode.Kind != yaml.MappingNode {
+				continue
+			}
+
+			for j := 0; j+1 < len(mergedNode.Content); j += 2 {
+				key := mergedNode.Content[j].Value
+				if ownKeys[key] || mergedKeys[key] {
+					continue
+				}
+
+				mergedKeys[key] = true
+				expanded.Content = append(expanded.Content, mergedNode.Content[j], mergedNode.Content[j+1])
+			}
+		}
+	}
+
+	return &expanded
+}
+
+// Looks up a field of a service, with the merge keys of its value expanded
+func lookupComposeNode(node *yaml.Node, key string) *yaml.Node {
+	return expandMergeKeys(lookupNode(node, key))
+}
+
+func createEnvironmentRow(key string, value *yaml.Node, commentNode *yaml.Node) ValueRow {
+	description := getDescriptionFromNode(commentNode)
+	row := ValueRow{
+		Key:         key,
+		Type:        stringType,
+		Description: description.Description,
+		Column:      commentNode.Column,
+		LineNumber:  commentNode.Line,
+	}
+
+	if description.Default != "" {
+		row.Default = description.Default
+	} else if value != nil && value.Tag != nullTag {
+		row.Default = fmt.Sprintf("`%s`", value.Value)
+	}
+
+	return row
+}
+
+// Environment variables are either a mapping of variables to values, or a list of VARIABLE=value entries. Variables
+// without a value are passed from the shell running compose, and have no default. Mappings may merge in variables
+// shared between services with `<<: *anchor`.
+func createEnvironmentRows(node *yaml.Node, options Options) []ValueRow {
+	rows := make([]ValueRow, 0)
+	if node == nil {
+		return rows
+	}
+
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, entryNode := range node.Content {
+			entry := strings.SplitN(resolveAlias(entryNode).Value, "=", 2)
+			var valueNode *yaml.Node
+			if len(entry) == 2 {
+				valueNode = &yaml.Node{Kind: yaml.ScalarNode, Value: entry[1]}
+			}
+
+			rows = append(rows, createEnvironmentRow(entry[0], valueNode, entryNode))
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			rows = append(rows, createEnvironmentRow(node.Content[i].Value, resolveAlias(node.Content[i+1]), node.Content[i]))
+		}
+	}
+
+	sortValueRows(rows, options.SortValuesOrder)
+
+	return rows
+}
+
+// Services may merge in fields shared between services with `<<: *anchor`
+func getComposeService(keyNode *yaml.Node, serviceNode *yaml.Node, options Options) (ComposeService, error) {
+	serviceNode = expandMergeKeys(serviceNode)
+
+	var service composeService
+	if err := serviceNode.Decode(&service); err != nil {
+		return ComposeService{}, err
+	}
+
+	ports, err := getComposePorts(lookupComposeNode(serviceNode, "ports"))
+	if err != nil {
+		return ComposeService{}, err
+	}
+
+	volumes, err := getComposeVolumes(lookupComposeNode(serviceNode, "volumes"))
+	if err != nil {
+		return ComposeService{}, err
+	}
+
+	// The build context is either the value of build, or its context field
+	build := ""
+	if buildNode := lookupComposeNode(serviceNode, "build"); buildNode != nil && buildNode.Kind == yaml.ScalarNode {
+		build = buildNode.Value
+	} else if contextNode := lookupComposeNode(buildNode, "context"); contextNode != nil {
+		build = contextNode.Value
+	}
+
+	return ComposeService{
+		Name:             keyNode.Value,
+		Description:      getDescriptionFromNode(keyNode).Description,
+		Image:            service.Image,
+		Build:            build,
+		Ports:            ports,
+		Volumes:          volumes,
+		DependsOn:        getComposeDependencies(lookupComposeNode(serviceNode, "depends_on")),
+		Values:           createEnvironmentRows(lookupComposeNode(serviceNode, "environment"), options),
+		LongDefaultStyle: options.LongDefaultStyle,
+	}, nil
+}
+
+// Each compose file is documented on its own, override files aren't merged into the services they override
+func getComposeServices(options Options) ([]ComposeService, []diagnostic.Diagnostic, error) {
+	services := make([]ComposeService, 0)
+	warnings := make([]diagnostic.Diagnostic, 0)
+
+	for _, file := range options.ValuesFiles {
+		compose, fileWarnings, err := helm.ParseValuesFS(options.FileSystem(), []string{file})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		warnings = append(warnings, fileWarnings...)
+
+		servicesNode := lookupNode(compose, "services")
+		if servicesNode == nil || servicesNode.Kind != yaml.MappingNode {
+			continue
+		}
+
+		for i := 0; i+1 < len(servicesNode.Content); i += 2 {
+			service, err := getComposeService(servicesNode.Content[i], servicesNode.Content[i+1], options)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: invalid service %s: %w", file, servicesNode.Content[i].Value, err)
+			}
+
+			services = append(services, service)
+		}
+	}
+
+	if len(services) == 0 {
+		return nil, nil, fmt.Errorf("no services found in %s", strings.Join(options.ValuesFiles, ", "))
+	}
+
+	return services, warnings, nil
+}
+
+func generateCompose(options Options) ([]byte, []diagnostic.Diagnostic, error) {
+	services, warnings, err := getComposeServices(options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output, templateWarnings, err := renderProfileMarkdown(TemplateData{ComposeServices: services}, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return output, append(warnings, templateWarnings...), nil
+}
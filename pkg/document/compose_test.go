@@ -0,0 +1,189 @@
+package document
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const devStack = `services:
+  # -- Public API of the application
+  api:
+    build:
+      context: ./api
+    ports:
+      - "8080:80"
+      - 127.0.0.1:9229:9229/udp
+      - target: 443
+        published: 8443
+    volumes:
+      - ./api:/app:ro
+      - /app/tmp
+    environment:
+      # -- Log level of the API
+      LOG_LEVEL: debug
+      # -- Token of the payment provider, taken from the shell
+      PAYMENT_TOKEN:
+    depends_on:
+      db:
+        condition: service_healthy
+  db:
+    image: postgres:16
+    environment:
+      # -- Password of the postgres user
+      - POSTGRES_PASSWORD=app
+      - POSTGRES_USER
+    volumes:
+      - type: volume
+        source: db-data
+        target: /var/lib/postgresql/data
+        read_only: true
+volumes:
+  db-data:
+`
+
+func TestGetComposeServices(t *testing.T) {
+	fsys := fstest.MapFS{
+		"compose.yaml": {Data: []byte(devStack)},
+	}
+
+	services, warnings, err := getComposeServices(Options{FS: fsys, ValuesFiles: []string{"compose.yaml"}, SortValuesOrder: FileSortOrder})
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+	require.Len(t, services, 2)
+
+	api := services[0]
+	assert.Equal(t, "api", api.Name)
+	assert.Equal(t, "Public API of the application", api.Description)
+	assert.Equal(t, "./api", api.Build)
+	assert.Equal(t, []ComposePort{
+		{Published: "8080", Target: "80"},
+		{HostIP: "127.0.0.1", Published: "9229", Target: "9229", Protocol: "udp"},
+		{Published: "8443", Target: "443"},
+	}, api.Ports)
+	assert.Equal(t, []ComposeVolume{
+		{Source: "./api", Target: "/app", Mode: "ro"},
+		{Target: "/app/tmp"},
+	}, api.Volumes)
+	assert.Equal(t, []ComposeDependency{{Name: "db", Condition: "service_healthy"}}, api.DependsOn)
+	assert.Equal(t, []ValueRow{
+		{Key: "LOG_LEVEL", Type: "string", Default: "`debug`", Description: "Log level of the API", Column: 7, LineNumber: 16},
+		{Key: "PAYMENT_TOKEN", Type: "string", Description: "Token of the payment provider, taken from the shell", Column: 7, LineNumber: 18},
+	}, api.Values)
+
+	db := services[1]
+	assert.Equal(t, "postgres:16", db.Image)
+	assert.Empty(t, db.DependsOn)
+	assert.Equal(t, []ComposeVolume{{Source: "db-data", Target: "/var/lib/postgresql/data", Mode: "ro"}}, db.Volumes)
+	assert.Equal(t, []ValueRow{
+		{Key: "POSTGRES_PASSWORD", Type: "string", Default: "`app`", Description: "Password of the postgres user", Column: 9, LineNumber: 26},
+		{Key: "POSTGRES_USER", Type: "string", Column: 9, LineNumber: 27},
+	}, db.Values)
+}
+
+const sharedStack = `x-logging: &logging
+  # -- Log level of the service
+  LOG_LEVEL: info
+  # -- Log format of the service
+  LOG_FORMAT: json
+x-worker: &worker
+  image: app:1.0
+  environment:
+    <<: *logging
+    # -- Queue the worker consumes
+    QUEUE: default
+x-region: &region eu-west-1
+services:
+  web:
+    image: app:1.0
+    environment:
+      <<: *logging
+      # -- Log level of the web server
+      LOG_LEVEL: debug
+      # -- Region of the deployment
+      REGION: *region
+  worker:
+    <<: *worker
+    ports:
+      - "9090"
+`
+
+func TestGetComposeServicesMergeKeys(t *testing.T) {
+	fsys := fstest.MapFS{
+		"compose.yaml": {Data: []byte(sharedStack)},
+	}
+
+	services, _, err := getComposeServices(Options{FS: fsys, ValuesFiles: []string{"compose.yaml"}, SortValuesOrder: FileSortOrder})
+	require.NoError(t, err)
+	require.Len(t, services, 2)
+
+	assert.Equal(t, []ValueRow{
+		{Key: "LOG_FORMAT", Type: "string", Default: "`json`", Description: "Log format of the service", Column: 3, LineNumber: 5},
+		{Key: "LOG_LEVEL", Type: "string", Default: "`debug`", Description: "Log level of the web server", Column: 7, LineNumber: 19},
+		{Key: "REGION", Type: "string", Default: "`eu-west-1`", Description: "Region of the deployment", Column: 7, LineNumber: 21},
+	}, services[0].Values)
+
+	worker := services[1]
+	assert.Equal(t, "app:1.0", worker.Image)
+	assert.Equal(t, []ComposePort{{Target: "9090"}}, worker.Ports)
+	assert.Equal(t, []ValueRow{
+		{Key: "LOG_LEVEL", Type: "string", Default: "`info`", Description: "Log level of the service", Column: 3, LineNumber: 3},
+		{Key: "LOG_FORMAT", Type: "string", Default: "`json`", Description: "Log format of the service", Column: 3, LineNumber: 5},
+		{Key: "QUEUE", Type: "string", Default: "`default`", Description: "Queue the worker consumes", Column: 5, LineNumber: 11},
+	}, worker.Values)
+}
+
+func TestGetComposeServicesWithoutServices(t *testing.T) {
+	fsys := fstest.MapFS{
+		"values.yaml": {Data: []byte("replicas: 1\n")},
+	}
+
+	_, _, err := getComposeServices(Options{FS: fsys, ValuesFiles: []string{"values.yaml"}})
+	assert.EqualError(t, err, "no services found in values.yaml")
+}
+
+func TestGenerateCompose(t *testing.T) {
+	fsys := fstest.MapFS{
+		"compose.yaml": {Data: []byte(devStack)},
+	}
+
+	output, err := Generate(Options{FS: fsys, ValuesFiles: []string{"compose.yaml"}, Profile: ComposeProfile})
+	require.NoError(t, err)
+
+	assert.Equal(t, "## api\n\n"+
+		"Public API of the application\n\n"+
+		"- **Build:** `./api`\n"+
+		"- **Depends on:** `db` (service_healthy)\n\n"+
+		"### Ports\n\n"+
+		"| Host | Container | Protocol |\n"+
+		"|------|-----------|----------|\n"+
+		"| 8080 | 80 | tcp |\n"+
+		"| 127.0.0.1:9229 | 9229 | udp |\n"+
+		"| 8443 | 443 | tcp |\n\n"+
+		"### Volumes\n\n"+
+		"| Source | Target | Mode |\n"+
+		"|--------|--------|------|\n"+
+		"| ./api | /app | ro |\n"+
+		"|  | /app/tmp | rw |\n\n"+
+		"### Environment\n\n"+
+		"| Variable | Value | Description |\n"+
+		"|----------|-------|-------------|\n"+
+		"| LOG\\_LEVEL | `debug` | Log level of the API |\n"+
+		"| PAYMENT\\_TOKEN |  | Token of the payment provider, taken from the shell |\n\n"+
+		"## db\n\n"+
+		"- **Image:** `postgres:16`\n\n"+
+		"### Volumes\n\n"+
+		"| Source | Target | Mode |\n"+
+		"|--------|--------|------|\n"+
+		"| db-data | /var/lib/postgresql/data | ro |\n\n"+
+		"### Environment\n\n"+
+		"| Variable | Value | Description |\n"+
+		"|----------|-------|-------------|\n"+
+		"| POSTGRES\\_PASSWORD | `app` | Password of the postgres user |\n"+
+		"| POSTGRES\\_USER |  |  |\n\n", string(output))
+
+	_, err = Generate(Options{FS: fsys, ValuesFiles: []string{"compose.yaml"}, Profile: ComposeProfile, OutputFormat: CSVOutputFormat})
+	assert.EqualError(t, err, "output format csv is not supported by the compose profile")
+}
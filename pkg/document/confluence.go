@@ -0,0 +1,94 @@
+package document
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Defaults longer than this are folded into an expand macro so large objects don't blow up the width of the table
+const confluenceExpandThreshold = 80
+
+var markdownLinkRegex = regexp.MustCompile(`\[([^\]]*)\]\(([^)\s]*)\)`)
+var markdownCodeSpanRegex = regexp.MustCompile("`([^`]*)`")
+
+// Descriptions are written with markdown tables in mind, so the common inline markup (links and code spans) is carried
+// over and everything else is escaped
+func confluenceDescription(description string) string {
+	escaped := html.EscapeString(description)
+	escaped = markdownLinkRegex.ReplaceAllString(escaped, `<a href="$2">$1</a>`)
+	escaped = markdownCodeSpanRegex.ReplaceAllString(escaped, `<code>$1</code>`)
+
+	return escaped
+}
+
+// CDATA sections can't contain their own terminator, so it is split across two sections
+func confluenceCDATA(text string) string {
+	return "<![CDATA[" + strings.ReplaceAll(text, "]]>", "]]]]><![CDATA[>") + "]]>"
+}
+
+func confluenceCodeMacro(code string, language string) string {
+	return `<ac:structured-macro ac:name="code">` +
+		`<ac:parameter ac:name="language">` + language + `</ac:parameter>` +
+		`<ac:plain-text-body>` + confluenceCDATA(code) + `</ac:plain-text-body>` +
+		`</ac:structured-macro>`
+}
+
+func confluenceExpandMacro(title string, body string) string {
+	return `<ac:structured-macro ac:name="expand">` +
+		`<ac:parameter ac:name="title">` + html.EscapeString(title) + `</ac:parameter>` +
+		`<ac:rich-text-body>` + body + `</ac:rich-text-body>` +
+		`</ac:structured-macro>`
+}
+
+// Language of the code macro highlighting defaults rendered in a default format
+func confluenceLanguage(defaultFormat string) string {
+	if defaultFormat == YAMLDefaultFormat || defaultFormat == FlowYAMLDefaultFormat {
+		return "yaml"
+	}
+
+	return "json"
+}
+
+func confluenceDefault(row ValueRow, defaultFormat string) string {
+	if !isCodeSpan(row.Default) {
+		return confluenceDescription(row.Default)
+	}
+
+	// Long defaults have been moved out of the inline default, and are always rendered as yaml
+	if row.LongDefault != "" {
+		return confluenceExpandMacro("Show default", confluenceCodeMacro(row.LongDefault, "yaml"))
+	}
+
+	defaultValue := plainDefault(row.Default)
+	if len(defaultValue) > confluenceExpandThreshold {
+		return confluenceExpandMacro("Show default", confluenceCodeMacro(defaultValue, confluenceLanguage(defaultFormat)))
+	}
+
+	return confluenceCodeMacro(defaultValue, confluenceLanguage(defaultFormat))
+}
+
+func renderConfluence(output io.Writer, rows []ValueRow, defaultFormat string) error {
+	var page strings.Builder
+
+	page.WriteString("<table><tbody>\n")
+	page.WriteString("<tr><th>Key</th><th>Type</th><th>Default</th><th>Description</th></tr>\n")
+
+	for _, row := range rows {
+		fmt.Fprintf(
+			&page,
+			"<tr><td><code>%s</code></td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+			html.EscapeString(row.Key),
+			html.EscapeString(row.Type),
+			confluenceDefault(row, defaultFormat),
+			confluenceDescription(row.Description),
+		)
+	}
+
+	page.WriteString("</tbody></table>\n")
+
+	_, err := io.WriteString(output, page.String())
+	return err
+}
@@ -0,0 +1,50 @@
+package document
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderConfluence(t *testing.T) {
+	yamlValues := parseYamlValues(`
+# -- See [the docs](https://example.com) for <all> options
+# @default -- computed by the chart
+name: app
+port: 8080
+	`)
+
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
+	require.NoError(t, err)
+
+	var output bytes.Buffer
+	err = renderConfluence(&output, valuesRows, "")
+	require.NoError(t, err)
+
+	const expected = `<table><tbody>
+<tr><th>Key</th><th>Type</th><th>Default</th><th>Description</th></tr>
+<tr><td><code>name</code></td><td>string</td><td>computed by the chart</td><td>See <a href="https://example.com">the docs</a> for &lt;all&gt; options</td></tr>
+<tr><td><code>port</code></td><td>int</td><td><ac:structured-macro ac:name="code"><ac:parameter ac:name="language">json</ac:parameter><ac:plain-text-body><![CDATA[8080]]></ac:plain-text-body></ac:structured-macro></td><td></td></tr>
+</tbody></table>
+`
+	assert.Equal(t, expected, output.String())
+}
+
+func TestConfluenceDefaultExpandsLongObjects(t *testing.T) {
+	longDefault := "`[" + strings.Repeat(`"]]>",`, 20) + `"x"]` + "`"
+	rendered := confluenceDefault(ValueRow{Key: "list", Type: listType, Default: longDefault}, JSONDefaultFormat)
+
+	assert.True(t, strings.HasPrefix(rendered, `<ac:structured-macro ac:name="expand">`))
+	assert.Equal(t, 20, strings.Count(rendered, "]]]]><![CDATA[>"))
+}
+
+func TestConfluenceDefaultLanguage(t *testing.T) {
+	row := ValueRow{Key: "port", Type: intType, Default: "`8080`"}
+
+	assert.Contains(t, confluenceDefault(row, JSONDefaultFormat), `<ac:parameter ac:name="language">json</ac:parameter>`)
+	assert.Contains(t, confluenceDefault(row, YAMLDefaultFormat), `<ac:parameter ac:name="language">yaml</ac:parameter>`)
+	assert.Contains(t, confluenceDefault(row, FlowYAMLDefaultFormat), `<ac:parameter ac:name="language">yaml</ac:parameter>`)
+}
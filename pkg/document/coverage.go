@@ -0,0 +1,222 @@
+package document
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CoverageKey is an undocumented key of a values file
+type CoverageKey struct {
+	Key        string
+	LineNumber int
+	Column     int
+}
+
+// SubtreeCoverage counts the documented keys under a single top level key, including the key itself
+type SubtreeCoverage struct {
+	Key        string
+	Documented int
+	Total      int
+}
+
+// Coverage describes how many keys of a values file carry a description. Leaf values are counted, as are objects and
+// lists with a description of their own, whose keys are documented along with them as they are in the values table.
+// Undocumented objects and lists are only counted through the keys in them, unless they are empty.
+type Coverage struct {
+	File         string
+	Documented   int
+	Total        int
+	Subtrees     []SubtreeCoverage
+	Undocumented []CoverageKey
+}
+
+func coveragePercent(documented int, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+
+	return float64(documented) * 100 / float64(total)
+}
+
+// Percent returns the percentage of documented keys, files without any keys are fully documented
+func (c Coverage) Percent() float64 {
+	return coveragePercent(c.Documented, c.Total)
+}
+
+// Percent returns the percentage of documented keys in the subtree
+func (s SubtreeCoverage) Percent() float64 {
+	return coveragePercent(s.Documented, s.Total)
+}
+
+// TotalCoverage returns the percentage of documented keys across all the given files
+func TotalCoverage(coverages []Coverage) float64 {
+	documented := 0
+	total := 0
+
+	for _, c := range coverages {
+		documented += c.Documented
+		total += c.Total
+	}
+
+	return coveragePercent(documented, total)
+}
+
+func addDocumentedCoverage(coverage *Coverage, subtree *SubtreeCoverage) {
+	coverage.Total++
+	coverage.Documented++
+	subtree.Total++
+	subtree.Documented++
+}
+
+// Returns the number of keys counted for the key and everything under it
+func addKeyCoverage(coverage *Coverage, subtree *SubtreeCoverage, key string, keyNode *yaml.Node, value *yaml.Node) int {
+	if getDescriptionFromNode(keyNode).Description != "" {
+		addDocumentedCoverage(coverage, subtree)
+		return 1
+	}
+
+	// Undocumented objects and lists are only counted through the keys in them, unless there are none
+	if counted := addValueCoverage(coverage, subtree, key, value); counted > 0 {
+		return counted
+	}
+
+	coverage.Total++
+	subtree.Total++
+	coverage.Undocumented = append(coverage.Undocumented, CoverageKey{
+		Key:        key,
+		LineNumber: keyNode.Line,
+		Column:     keyNode.Column,
+	})
+
+	return 1
+}
+
+// List items aren't keys of their own, but the keys of objects in lists are counted, and described items count as a
+// documented key
+func addValueCoverage(coverage *Coverage, subtree *SubtreeCoverage, prefix string, value *yaml.Node) int {
+	counted := 0
+
+	switch value.Kind {
+	case yaml.AliasNode:
+		counted += addValueCoverage(coverage, subtree, prefix, value.Alias)
+	case yaml.MappingNode:
+		for i := 0; i < len(value.Content); i += 2 {
+			k := value.Content[i]
+			counted += addKeyCoverage(coverage, subtree, formatNextObjectKeyPrefix(prefix, k.Value), k, value.Content[i+1])
+		}
+	case yaml.SequenceNode:
+		for i, item := range value.Content {
+			if getDescriptionFromNode(item).Description != "" {
+				addDocumentedCoverage(coverage, subtree)
+				counted++
+				continue
+			}
+
+			counted += addValueCoverage(coverage, subtree, formatNextListKeyPrefix(prefix, i), item)
+		}
+	}
+
+	return counted
+}
+
+// GetCoverage counts the keys of a parsed values file which carry a description, per top level key and overall
+func GetCoverage(valuesData *yaml.Node, file string) (Coverage, error) {
+	coverage := Coverage{
+		File:         file,
+		Subtrees:     make([]SubtreeCoverage, 0),
+		Undocumented: make([]CoverageKey, 0),
+	}
+
+	// Empty values files have nothing to document
+	if valuesData.Kind == 0 || len(valuesData.Content) == 0 {
+		return coverage, nil
+	}
+
+	if valuesData.Kind != yaml.DocumentNode || valuesData.Content[0].Kind != yaml.MappingNode {
+		return Coverage{}, fmt.Errorf("values file %s must resolve to a map", file)
+	}
+
+	root := valuesData.Content[0]
+	for i := 0; i < len(root.Content); i += 2 {
+		k := root.Content[i]
+		key := formatNextObjectKeyPrefix("", k.Value)
+		subtree := SubtreeCoverage{Key: key}
+
+		addKeyCoverage(&coverage, &subtree, key, k, root.Content[i+1])
+		coverage.Subtrees = append(coverage.Subtrees, subtree)
+	}
+
+	return coverage, nil
+}
+
+// RenderCoverageText writes a plain text coverage report meant for the terminal
+func RenderCoverageText(output io.Writer, coverages []Coverage) error {
+	writer := tabwriter.NewWriter(output, 0, 4, 2, ' ', 0)
+
+	for _, c := range coverages {
+		fmt.Fprintf(writer, "%s: %d/%d keys documented (%.1f%%)\n", c.File, c.Documented, c.Total, c.Percent())
+
+		for _, s := range c.Subtrees {
+			fmt.Fprintf(writer, "  %s\t%d/%d\t%.1f%%\n", s.Key, s.Documented, s.Total, s.Percent())
+		}
+
+		for _, k := range c.Undocumented {
+			fmt.Fprintf(writer, "  undocumented: %s (%s:%d)\n", k.Key, c.File, k.LineNumber)
+		}
+	}
+
+	if len(coverages) > 1 {
+		fmt.Fprintf(writer, "total: %.1f%% of keys documented\n", TotalCoverage(coverages))
+	}
+
+	return writer.Flush()
+}
+
+// RenderCoverageMarkdown writes a coverage summary meant to be posted as a pull request comment. A minCoverage above
+// zero is shown along with whether it was met.
+func RenderCoverageMarkdown(output io.Writer, coverages []Coverage, minCoverage float64) error {
+	total := TotalCoverage(coverages)
+
+	fmt.Fprintf(output, "## Documentation coverage\n\n**%.1f%%** of values keys are documented", total)
+	if minCoverage > 0 {
+		status := "met"
+		if total < minCoverage {
+			status = "not met"
+		}
+
+		fmt.Fprintf(output, ", the minimum of %.1f%% is %s", minCoverage, status)
+	}
+
+	fmt.Fprint(output, ".\n")
+
+	for _, c := range coverages {
+		fmt.Fprintf(output, "\n### %s\n\n", markdownCodeSpan(c.File))
+		fmt.Fprintf(output, "%d of %d keys documented (%.1f%%)\n", c.Documented, c.Total, c.Percent())
+
+		if len(c.Subtrees) == 0 {
+			continue
+		}
+
+		fmt.Fprint(output, "\n| Key | Documented | Coverage |\n|-----|------------|----------|\n")
+
+		for _, s := range c.Subtrees {
+			fmt.Fprintf(output, "| %s | %d / %d | %.1f%% |\n", escapeKey(GitHubEscapeFormat, s.Key), s.Documented, s.Total, s.Percent())
+		}
+
+		if len(c.Undocumented) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(output, "\n<details><summary>%d undocumented keys</summary>\n\n", len(c.Undocumented))
+		for _, k := range c.Undocumented {
+			fmt.Fprintf(output, "* %s (line %d)\n", markdownCodeSpan(k.Key), k.LineNumber)
+		}
+
+		fmt.Fprint(output, "\n</details>\n")
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package document
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const coverageValues = `
+# -- Number of replicas
+replicas: 1
+image:
+  # -- Image repository
+  repository: nginx
+  tag: latest
+# -- Resources, documented as a whole
+resources:
+  limits:
+    cpu: 100m
+hosts:
+  - name: example.com
+    # -- Paths served
+    paths: []
+  # -- Described item
+  - name: other.com
+ingress:
+  # -- Class of the ingress
+  className: nginx
+  annotations: {}
+`
+
+func TestGetCoverage(t *testing.T) {
+	var valuesData yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(strings.TrimSpace(coverageValues)), &valuesData))
+
+	coverage, err := GetCoverage(&valuesData, "values.yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, 6, coverage.Documented)
+	assert.Equal(t, 9, coverage.Total)
+	assert.InDelta(t, 66.7, coverage.Percent(), 0.1)
+
+	assert.Equal(t, []SubtreeCoverage{
+		{Key: "replicas", Documented: 1, Total: 1},
+		{Key: "image", Documented: 1, Total: 2},
+		{Key: "resources", Documented: 1, Total: 1},
+		{Key: "hosts", Documented: 2, Total: 3},
+		{Key: "ingress", Documented: 1, Total: 2},
+	}, coverage.Subtrees)
+
+	assert.Equal(t, []CoverageKey{
+		{Key: "image.tag", LineNumber: 6, Column: 3},
+		{Key: "hosts[0].name", LineNumber: 12, Column: 5},
+		{Key: "ingress.annotations", LineNumber: 20, Column: 3},
+	}, coverage.Undocumented)
+}
+
+func TestGetCoverageEmptyValues(t *testing.T) {
+	coverage, err := GetCoverage(&yaml.Node{}, "values.yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, coverage.Total)
+	assert.Equal(t, 100.0, coverage.Percent())
+}
+
+func TestTotalCoverage(t *testing.T) {
+	assert.Equal(t, 25.0, TotalCoverage([]Coverage{{Documented: 1, Total: 1}, {Documented: 0, Total: 3}}))
+	assert.Equal(t, 100.0, TotalCoverage([]Coverage{}))
+}
+
+func TestRenderCoverageMarkdown(t *testing.T) {
+	coverages := []Coverage{{
+		File:         "values.yaml",
+		Documented:   1,
+		Total:        2,
+		Subtrees:     []SubtreeCoverage{{Key: "image", Documented: 1, Total: 2}},
+		Undocumented: []CoverageKey{{Key: "image.tag", LineNumber: 3, Column: 3}},
+	}}
+
+	var output bytes.Buffer
+	require.NoError(t, RenderCoverageMarkdown(&output, coverages, 80))
+
+	assert.Equal(t, "## Documentation coverage\n\n"+
+		"**50.0%** of values keys are documented, the minimum of 80.0% is not met.\n\n"+
+		"### `values.yaml`\n\n"+
+		"1 of 2 keys documented (50.0%)\n\n"+
+		"| Key | Documented | Coverage |\n|-----|------------|----------|\n"+
+		"| image | 1 / 2 | 50.0% |\n\n"+
+		"<details><summary>1 undocumented keys</summary>\n\n"+
+		"* `image.tag` (line 3)\n\n"+
+		"</details>\n", output.String())
+}
+
+func TestRenderCoverageText(t *testing.T) {
+	coverages := []Coverage{{
+		File:         "values.yaml",
+		Documented:   1,
+		Total:        2,
+		Subtrees:     []SubtreeCoverage{{Key: "image", Documented: 1, Total: 2}},
+		Undocumented: []CoverageKey{{Key: "image.tag", LineNumber: 3, Column: 3}},
+	}}
+
+	var output bytes.Buffer
+	require.NoError(t, RenderCoverageText(&output, coverages))
+
+	assert.Equal(t, "values.yaml: 1/2 keys documented (50.0%)\n"+
+		"  image  1/2  50.0%\n"+
+		"  undocumented: image.tag (values.yaml:3)\n", output.String())
+}
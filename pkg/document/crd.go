@@ -0,0 +1,396 @@
+package document
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+	"github.com/theEndBeta/yaml-docs/pkg/helm"
+)
+
+const customResourceDefinitionKind = "CustomResourceDefinition"
+
+// CustomResource is a CustomResourceDefinition documented with CRDProfile
+type CustomResource struct {
+	// Name of the definition, <plural>.<group>
+	Name     string
+	Group    string
+	Kind     string
+	Plural   string
+	Scope    string
+	Versions []CustomResourceVersion
+}
+
+// CustomResourceVersion is one version of a CustomResource, with a row for each field of its schema
+type CustomResourceVersion struct {
+	Name               string
+	Served             bool
+	Storage            bool
+	Deprecated         bool
+	DeprecationWarning string
+	Values             []ValueRow
+	LongDefaultStyle   string
+}
+
+type customResourceDefinition struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name string `yaml:"name"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Group string `yaml:"group"`
+		Names struct {
+			Kind   string `yaml:"kind"`
+			Plural string `yaml:"plural"`
+		} `yaml:"names"`
+		Scope string `yaml:"scope"`
+
+		// apiextensions.k8s.io/v1beta1 definitions may have a single version, and a schema shared by all versions
+		Version  string                  `yaml:"version"`
+		Versions []customResourceVersion `yaml:"versions"`
+	} `yaml:"spec"`
+
+	sharedSchema *yaml.Node
+}
+
+type customResourceVersion struct {
+	Name               string `yaml:"name"`
+	Served             bool   `yaml:"served"`
+	Storage            bool   `yaml:"storage"`
+	Deprecated         bool   `yaml:"deprecated"`
+	DeprecationWarning string `yaml:"deprecationWarning"`
+
+	schema *yaml.Node
+}
+
+// The parts of an OpenAPI v3 schema that are documented. Nested schemas and values are looked up as nodes, rather than
+// decoded, to keep properties in file order and the line of each property.
+type openAPISchema struct {
+	Type                  string   `yaml:"type"`
+	Format                string   `yaml:"format"`
+	Description           string   `yaml:"description"`
+	Required              []string `yaml:"required"`
+	IntOrString           bool     `yaml:"x-kubernetes-int-or-string"`
+	PreserveUnknownFields bool     `yaml:"x-kubernetes-preserve-unknown-fields"`
+
+	enum                 *yaml.Node
+	defaultValue         *yaml.Node
+	items                *yaml.Node
+	properties           *yaml.Node
+	additionalProperties *yaml.Node
+}
+
+// The value of a key in nested objects, nil if any of the keys doesn't exist
+func lookupNode(node *yaml.Node, keys ...string) *yaml.Node {
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	for _, key := range keys {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return nil
+		}
+
+		var value *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				value = node.Content[i+1]
+			}
+		}
+
+		node = value
+	}
+
+	return node
+}
+
+func decodeOpenAPISchema(node *yaml.Node) (openAPISchema, error) {
+	var schema openAPISchema
+	if node == nil || node.Kind != yaml.MappingNode {
+		return schema, nil
+	}
+
+	if err := node.Decode(&schema); err != nil {
+		return schema, err
+	}
+
+	schema.enum = lookupNode(node, "enum")
+	schema.defaultValue = lookupNode(node, "default")
+	schema.items = lookupNode(node, "items")
+	schema.properties = lookupNode(node, "properties")
+	schema.additionalProperties = lookupNode(node, "additionalProperties")
+
+	return schema, nil
+}
+
+func enumValues(schema openAPISchema) []*yaml.Node {
+	if schema.enum == nil || schema.enum.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	return schema.enum.Content
+}
+
+func getSchemaTypeName(schema openAPISchema) (string, error) {
+	if schema.IntOrString {
+		return "int-or-string", nil
+	}
+
+	typeName := schema.Type
+	if typeName == "array" {
+		items, err := decodeOpenAPISchema(schema.items)
+		if err != nil {
+			return "", err
+		}
+
+		itemsTypeName, err := getSchemaTypeName(items)
+		if err != nil {
+			return "", err
+		}
+
+		return "[]" + itemsTypeName, nil
+	}
+
+	if typeName == "" && (schema.properties != nil || schema.PreserveUnknownFields) {
+		typeName = objectType
+	} else if typeName == "" {
+		typeName = "any"
+	}
+
+	if schema.Format != "" {
+		typeName = fmt.Sprintf("%s (%s)", typeName, schema.Format)
+	}
+
+	return typeName, nil
+}
+
+func createSchemaRow(key string, keyNode *yaml.Node, schema openAPISchema, required bool, options Options) (ValueRow, error) {
+	typeName, err := getSchemaTypeName(schema)
+	if err != nil {
+		return ValueRow{}, err
+	}
+
+	row := ValueRow{
+		Key:         key,
+		Type:        typeName,
+		Description: strings.TrimSpace(schema.Description),
+		Required:    required,
+		Column:      keyNode.Column,
+		LineNumber:  keyNode.Line,
+	}
+
+	if schema.defaultValue != nil {
+		defaultValue, longDefault, err := formatDefaultValue(key, convertHelmValuesToJsonable(schema.defaultValue), options.DefaultFormat, options.DefaultMaxLength)
+		if err != nil {
+			return ValueRow{}, err
+		}
+
+		row.Default = fmt.Sprintf("`%s`", defaultValue)
+		row.LongDefault = longDefault
+	}
+
+	for _, value := range enumValues(schema) {
+		enumValue, err := marshalDefault(key, convertHelmValuesToJsonable(value), options.DefaultFormat)
+		if err != nil {
+			return ValueRow{}, err
+		}
+
+		row.Enum = append(row.Enum, fmt.Sprintf("`%s`", enumValue))
+	}
+
+	return row, nil
+}
+
+// Fields of objects are keyed by their dotted path, fields of the elements of lists by the path of the list followed
+// by [] and fields of maps with arbitrary keys by the path of the map followed by .*
+func createSchemaRows(prefix string, schema openAPISchema, options Options) ([]ValueRow, error) {
+	rows := make([]ValueRow, 0)
+	required := make(map[string]bool)
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	for i := 0; schema.properties != nil && i+1 < len(schema.properties.Content); i += 2 {
+		keyNode := schema.properties.Content[i]
+		key := formatNextObjectKeyPrefix(prefix, keyNode.Value)
+
+		property, err := decodeOpenAPISchema(schema.properties.Content[i+1])
+		if err != nil {
+			return nil, err
+		}
+
+		row, err := createSchemaRow(key, keyNode, property, required[keyNode.Value], options)
+		if err != nil {
+			return nil, err
+		}
+
+		propertyRows, err := createSchemaRows(key, property, options)
+		if err != nil {
+			return nil, err
+		}
+
+		rows = append(append(rows, row), propertyRows...)
+	}
+
+	nestedSchemas := []struct {
+		prefix string
+		node   *yaml.Node
+	}{
+		{prefix: prefix + "[]", node: schema.items},
+		{prefix: prefix + ".*", node: schema.additionalProperties},
+	}
+
+	for _, nested := range nestedSchemas {
+		// additionalProperties may be a boolean rather than a schema
+		if nested.node == nil || nested.node.Kind != yaml.MappingNode {
+			continue
+		}
+
+		nestedSchema, err := decodeOpenAPISchema(nested.node)
+		if err != nil {
+			return nil, err
+		}
+
+		nestedRows, err := createSchemaRows(nested.prefix, nestedSchema, options)
+		if err != nil {
+			return nil, err
+		}
+
+		rows = append(rows, nestedRows...)
+	}
+
+	return rows, nil
+}
+
+func getCustomResourceVersion(version customResourceVersion, sharedSchema *yaml.Node, options Options) (CustomResourceVersion, error) {
+	schemaNode := version.schema
+	if schemaNode == nil {
+		schemaNode = sharedSchema
+	}
+
+	schema, err := decodeOpenAPISchema(schemaNode)
+	if err != nil {
+		return CustomResourceVersion{}, err
+	}
+
+	rows, err := createSchemaRows("", schema, options)
+	if err != nil {
+		return CustomResourceVersion{}, err
+	}
+
+	sortValueRows(rows, options.SortValuesOrder)
+
+	return CustomResourceVersion{
+		Name:               version.Name,
+		Served:             version.Served,
+		Storage:            version.Storage,
+		Deprecated:         version.Deprecated,
+		DeprecationWarning: version.DeprecationWarning,
+		Values:             rows,
+		LongDefaultStyle:   options.LongDefaultStyle,
+	}, nil
+}
+
+func getCustomResource(definition customResourceDefinition, options Options) (CustomResource, error) {
+	versions := definition.Spec.Versions
+	if len(versions) == 0 && definition.Spec.Version != "" {
+		versions = []customResourceVersion{{Name: definition.Spec.Version, Served: true, Storage: true}}
+	}
+
+	resource := CustomResource{
+		Name:     definition.Metadata.Name,
+		Group:    definition.Spec.Group,
+		Kind:     definition.Spec.Names.Kind,
+		Plural:   definition.Spec.Names.Plural,
+		Scope:    definition.Spec.Scope,
+		Versions: make([]CustomResourceVersion, 0, len(versions)),
+	}
+
+	for _, version := range versions {
+		resourceVersion, err := getCustomResourceVersion(version, definition.sharedSchema, options)
+		if err != nil {
+			return CustomResource{}, fmt.Errorf("invalid schema of %s version %s: %w", resource.Name, version.Name, err)
+		}
+
+		resource.Versions = append(resource.Versions, resourceVersion)
+	}
+
+	return resource, nil
+}
+
+// Manifests may hold any number of documents, documents other than custom resource definitions are skipped
+func parseCustomResourceDefinitions(fsys fs.FS, file string) ([]customResourceDefinition, error) {
+	contents, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return nil, &helm.FileError{Path: file, Err: err}
+	}
+
+	definitions := make([]customResourceDefinition, 0)
+	decoder := yaml.NewDecoder(strings.NewReader(strings.ReplaceAll(string(contents), "\r\n", "\n")))
+
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			return definitions, nil
+		}
+
+		if err != nil {
+			return nil, &helm.ParseError{Path: file, Line: diagnostic.LineFromYamlError(err), Err: err}
+		}
+
+		var definition customResourceDefinition
+		if err := document.Decode(&definition); err != nil || definition.Kind != customResourceDefinitionKind {
+			continue
+		}
+
+		definition.sharedSchema = lookupNode(&document, "spec", "validation", "openAPIV3Schema")
+		if versions := lookupNode(&document, "spec", "versions"); versions != nil {
+			for i, version := range versions.Content {
+				definition.Spec.Versions[i].schema = lookupNode(version, "schema", "openAPIV3Schema")
+			}
+		}
+
+		definitions = append(definitions, definition)
+	}
+}
+
+func getCustomResources(options Options) ([]CustomResource, error) {
+	resources := make([]CustomResource, 0)
+
+	for _, file := range options.ValuesFiles {
+		definitions, err := parseCustomResourceDefinitions(options.FileSystem(), file)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, definition := range definitions {
+			resource, err := getCustomResource(definition, options)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+
+			resources = append(resources, resource)
+		}
+	}
+
+	if len(resources) == 0 {
+		return nil, fmt.Errorf("no %s found in %s", customResourceDefinitionKind, strings.Join(options.ValuesFiles, ", "))
+	}
+
+	return resources, nil
+}
+
+func generateCustomResources(options Options) ([]byte, []diagnostic.Diagnostic, error) {
+	resources, err := getCustomResources(options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return renderProfileMarkdown(TemplateData{CustomResources: resources}, options)
+}
@@ -0,0 +1,158 @@
+package document
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const widgetDefinition = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: widgets
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  names:
+    kind: Widget
+    plural: widgets
+  scope: Namespaced
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              description: Desired state of the widget.
+              required: [size]
+              properties:
+                size:
+                  type: string
+                  description: Size of the widget.
+                  enum: [small, large]
+                  default: small
+                ports:
+                  type: array
+                  items:
+                    type: object
+                    properties:
+                      port:
+                        x-kubernetes-int-or-string: true
+                labels:
+                  type: object
+                  additionalProperties:
+                    type: object
+                    properties:
+                      value:
+                        type: string
+                        format: byte
+    - name: v1beta1
+      served: false
+      storage: false
+      deprecated: true
+      deprecationWarning: example.com/v1beta1 Widget is deprecated
+`
+
+const legacyDefinition = `apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: gadgets.example.com
+spec:
+  group: example.com
+  version: v1alpha1
+  names:
+    kind: Gadget
+    plural: gadgets
+  scope: Cluster
+  validation:
+    openAPIV3Schema:
+      properties:
+        spec:
+          properties:
+            enabled:
+              type: boolean
+`
+
+func TestGetCustomResources(t *testing.T) {
+	fsys := fstest.MapFS{
+		"crds/widgets.yaml": {Data: []byte(widgetDefinition)},
+		"crds/gadgets.yaml": {Data: []byte(legacyDefinition)},
+	}
+
+	resources, err := getCustomResources(Options{FS: fsys, ValuesFiles: []string{"crds/widgets.yaml", "crds/gadgets.yaml"}})
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	widget := resources[0]
+	assert.Equal(t, "widgets.example.com", widget.Name)
+	assert.Equal(t, "Widget", widget.Kind)
+	require.Len(t, widget.Versions, 2)
+
+	assert.Equal(t, []ValueRow{
+		{Key: "spec", Type: "object", Description: "Desired state of the widget.", Column: 13, LineNumber: 24},
+		{Key: "spec.labels", Type: "object", Column: 17, LineNumber: 41},
+		{Key: "spec.labels.*.value", Type: "string (byte)", Column: 23, LineNumber: 46},
+		{Key: "spec.ports", Type: "[]object", Column: 17, LineNumber: 34},
+		{Key: "spec.ports[].port", Type: "int-or-string", Column: 23, LineNumber: 39},
+		{Key: "spec.size", Type: "string", Default: "`\"small\"`", Description: "Size of the widget.", Required: true, Enum: []string{"`\"small\"`", "`\"large\"`"}, Column: 17, LineNumber: 29},
+	}, widget.Versions[0].Values)
+
+	assert.Equal(t, "v1beta1", widget.Versions[1].Name)
+	assert.True(t, widget.Versions[1].Deprecated)
+	assert.False(t, widget.Versions[1].Served)
+	assert.Empty(t, widget.Versions[1].Values)
+
+	gadget := resources[1]
+	require.Len(t, gadget.Versions, 1)
+	assert.Equal(t, "v1alpha1", gadget.Versions[0].Name)
+	assert.Equal(t, []string{"spec", "spec.enabled"}, []string{gadget.Versions[0].Values[0].Key, gadget.Versions[0].Values[1].Key})
+	assert.Equal(t, "object", gadget.Versions[0].Values[0].Type)
+}
+
+func TestGetCustomResourcesWithoutDefinitions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"values.yaml": {Data: []byte("replicas: 1\n")},
+	}
+
+	_, err := getCustomResources(Options{FS: fsys, ValuesFiles: []string{"values.yaml"}})
+	assert.EqualError(t, err, "no CustomResourceDefinition found in values.yaml")
+}
+
+func TestGenerateCustomResources(t *testing.T) {
+	fsys := fstest.MapFS{
+		"crds.yaml": {Data: []byte(widgetDefinition)},
+	}
+
+	output, err := Generate(Options{FS: fsys, ValuesFiles: []string{"crds.yaml"}, Profile: CRDProfile})
+	require.NoError(t, err)
+
+	assert.Equal(t, "## Widget\n\n"+
+		"- **Group:** `example.com`\n"+
+		"- **Plural:** `widgets`\n"+
+		"- **Scope:** Namespaced\n\n"+
+		"### v1 (storage version)\n\n"+
+		"| Field | Type | Required | Default | Allowed Values | Description |\n"+
+		"|-------|------|----------|---------|----------------|-------------|\n"+
+		"| spec | object | no |  |  | Desired state of the widget. |\n"+
+		"| spec.labels | object | no |  |  |  |\n"+
+		"| spec.labels.\\*.value | string (byte) | no |  |  |  |\n"+
+		"| spec.ports | []object | no |  |  |  |\n"+
+		"| spec.ports[].port | int-or-string | no |  |  |  |\n"+
+		"| spec.size | string | yes | `\"small\"` | `\"small\"`, `\"large\"` | Size of the widget. |\n\n"+
+		"### v1beta1 (not served)\n\n"+
+		"> **Deprecated:** example.com/v1beta1 Widget is deprecated\n\n"+
+		"The schema of this version has no fields.\n\n", string(output))
+
+	_, err = Generate(Options{FS: fsys, ValuesFiles: []string{"crds.yaml"}, Profile: CRDProfile, OutputFormat: CSVOutputFormat})
+	assert.EqualError(t, err, "output format csv is not supported by the crd profile")
+}
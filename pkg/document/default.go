@@ -0,0 +1,120 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	JSONDefaultFormat     = "json"
+	YAMLDefaultFormat     = "yaml"
+	FlowYAMLDefaultFormat = "flow-yaml"
+)
+
+const (
+	DetailsLongDefaultStyle  = "details"
+	FootnoteLongDefaultStyle = "footnote"
+)
+
+// Length of the inline summary of a multi-line default when no maximum length is configured
+const defaultSummaryLength = 40
+
+func setYamlFlowStyle(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode || node.Kind == yaml.SequenceNode {
+		node.Style = yaml.FlowStyle
+	}
+
+	for _, child := range node.Content {
+		setYamlFlowStyle(child)
+	}
+}
+
+func yamlMarshalDefault(key string, value interface{}, flow bool) (string, error) {
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return "", fmt.Errorf("failed to marshal default value for %s to yaml: %s", key, err)
+	}
+
+	if flow {
+		setYamlFlowStyle(&node)
+	}
+
+	outputBuffer := &bytes.Buffer{}
+	valueEncoder := yaml.NewEncoder(outputBuffer)
+	valueEncoder.SetIndent(2)
+
+	if err := valueEncoder.Encode(&node); err != nil {
+		return "", fmt.Errorf("failed to marshal default value for %s to yaml: %s", key, err)
+	}
+
+	return strings.TrimRight(outputBuffer.String(), "\n"), nil
+}
+
+func marshalDefault(key string, value interface{}, defaultFormat string) (string, error) {
+	switch defaultFormat {
+	case YAMLDefaultFormat:
+		return yamlMarshalDefault(key, value, false)
+	case FlowYAMLDefaultFormat:
+		return yamlMarshalDefault(key, value, true)
+	case "", JSONDefaultFormat:
+		return jsonMarshalNoEscape(key, value)
+	}
+
+	log.Infof("Invalid default format `%s`, defaulting to %s", defaultFormat, JSONDefaultFormat)
+	return jsonMarshalNoEscape(key, value)
+}
+
+func truncateDefault(defaultValue string, maxLength int) string {
+	if maxLength <= 0 {
+		maxLength = defaultSummaryLength
+	}
+
+	runes := []rune(defaultValue)
+	if len(runes) <= maxLength {
+		return defaultValue
+	}
+
+	return string(runes[:maxLength]) + "…"
+}
+
+// Returns the inline default for the values table, and for defaults that are too long or span multiple lines, a yaml
+// rendering of the full value to be shown outside of the table
+func formatDefaultValue(key string, value interface{}, defaultFormat string, maxLength int) (string, string, error) {
+	formattedValue, err := marshalDefault(key, value, defaultFormat)
+	if err != nil {
+		return "", "", err
+	}
+
+	isMultiLine := strings.Contains(formattedValue, "\n")
+	if !isMultiLine && (maxLength <= 0 || len([]rune(formattedValue)) <= maxLength) {
+		return formattedValue, "", nil
+	}
+
+	longDefault, err := yamlMarshalDefault(key, value, false)
+	if err != nil {
+		return "", "", err
+	}
+
+	summary := formattedValue
+	if isMultiLine {
+		summary, err = yamlMarshalDefault(key, value, true)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	return truncateDefault(strings.ReplaceAll(summary, "\n", " "), maxLength), longDefault, nil
+}
+
+// The full default, for formats which have no problem with long or multi-line values
+func fullDefault(row ValueRow) string {
+	if row.LongDefault != "" {
+		return row.LongDefault
+	}
+
+	return plainDefault(row.Default)
+}
@@ -0,0 +1,80 @@
+package document
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/theEndBeta/yaml-docs/pkg/util"
+)
+
+func TestFormatDefaultValueFormats(t *testing.T) {
+	value := map[string]interface{}{"limits": map[string]interface{}{"cpu": "100m"}, "requests": []interface{}{1, 2}}
+
+	jsonValue, longValue, err := formatDefaultValue("resources", value, JSONDefaultFormat, 0)
+	require.NoError(t, err)
+	assert.Equal(t, `{"limits":{"cpu":"100m"},"requests":[1,2]}`, jsonValue)
+	assert.Equal(t, "", longValue)
+
+	flowValue, longValue, err := formatDefaultValue("resources", value, FlowYAMLDefaultFormat, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "{limits: {cpu: 100m}, requests: [1, 2]}", flowValue)
+	assert.Equal(t, "", longValue)
+
+	yamlValue, longValue, err := formatDefaultValue("resources", value, YAMLDefaultFormat, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "{limits: {cpu: 100m}, requests: [1, 2]}", yamlValue)
+	assert.Equal(t, "limits:\n  cpu: 100m\nrequests:\n  - 1\n  - 2", longValue)
+}
+
+func TestFormatDefaultValueTruncation(t *testing.T) {
+	value := []interface{}{"first", "second", "third"}
+
+	shortValue, longValue, err := formatDefaultValue("list", value, JSONDefaultFormat, 10)
+	require.NoError(t, err)
+	assert.Equal(t, `["first","…`, shortValue)
+	assert.Equal(t, "- first\n- second\n- third", longValue)
+
+	shortValue, longValue, err = formatDefaultValue("list", value, JSONDefaultFormat, 100)
+	require.NoError(t, err)
+	assert.Equal(t, `["first","second","third"]`, shortValue)
+	assert.Equal(t, "", longValue)
+}
+
+func TestValuesTableLongDefaults(t *testing.T) {
+	documentationTemplate, _, err := newDocumentationTemplate(util.OSFileSystem, []string{"testdata/nonexistent.md.gotmpl"}, ValuesProfile, GitHubEscapeFormat)
+	require.NoError(t, err)
+
+	rows := []ValueRow{
+		{Key: "image", Type: stringType, Default: "`nginx`"},
+		{Key: "volumes", Type: listType, Default: "`[{name: data}]`", LongDefault: "- name: data_dir", Description: "Volumes"},
+	}
+
+	var output bytes.Buffer
+	err = documentationTemplate.ExecuteTemplate(&output, "docs.valuesTable", TemplateData{Values: rows, LongDefaultStyle: DetailsLongDefaultStyle})
+	require.NoError(t, err)
+
+	const expectedDetails = "| Key | Type | Default | Description |\n" +
+		"|-----|------|---------|-------------|\n" +
+		"| image | string | `nginx` |  |\n" +
+		"| volumes | list | <details><summary>`[{name: data}]`</summary><pre lang=\"yaml\">- name: data&#95;dir</pre></details> | Volumes |"
+	assert.Equal(t, expectedDetails, output.String())
+
+	output.Reset()
+	err = documentationTemplate.ExecuteTemplate(&output, "docs.valuesTable", TemplateData{Values: rows, LongDefaultStyle: FootnoteLongDefaultStyle})
+	require.NoError(t, err)
+
+	const expectedFootnote = "| Key | Type | Default | Description |\n" +
+		"|-----|------|---------|-------------|\n" +
+		"| image | string | `nginx` |  |\n" +
+		"| volumes | list | `[{name: data}]`[^default-1] | Volumes |\n" +
+		"\n" +
+		"[^default-1]: Default value of volumes:\n" +
+		"\n" +
+		"    ```yaml\n" +
+		"    - name: data_dir\n" +
+		"    ```"
+	assert.Equal(t, expectedFootnote, output.String())
+}
@@ -0,0 +1,99 @@
+package document
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	KeyColumn         = "key"
+	TypeColumn        = "type"
+	DefaultColumn     = "default"
+	DescriptionColumn = "description"
+	LineColumn        = "line"
+)
+
+var defaultDelimitedColumns = []string{KeyColumn, TypeColumn, DefaultColumn, DescriptionColumn}
+
+func getDelimitedColumnValue(row ValueRow, column string) string {
+	switch column {
+	case KeyColumn:
+		return row.Key
+	case TypeColumn:
+		return row.Type
+	case DefaultColumn:
+		return fullDefault(row)
+	case DescriptionColumn:
+		return row.Description
+	case LineColumn:
+		return strconv.Itoa(row.LineNumber)
+	}
+
+	return ""
+}
+
+// Columns are matched case insensitively, and written to the header by their ids
+func normalizeDelimitedColumns(columns []string) ([]string, error) {
+	normalizedColumns := make([]string, 0, len(columns))
+
+	for _, column := range columns {
+		normalizedColumn := strings.ToLower(strings.TrimSpace(column))
+		if !isDelimitedColumn(normalizedColumn) {
+			return nil, fmt.Errorf("unknown column `%s`, must be one of (%s)", column, strings.Join(allDelimitedColumns(), ", "))
+		}
+
+		normalizedColumns = append(normalizedColumns, normalizedColumn)
+	}
+
+	return normalizedColumns, nil
+}
+
+func allDelimitedColumns() []string {
+	return []string{KeyColumn, TypeColumn, DefaultColumn, DescriptionColumn, LineColumn}
+}
+
+func isDelimitedColumn(column string) bool {
+	for _, knownColumn := range allDelimitedColumns() {
+		if column == knownColumn {
+			return true
+		}
+	}
+
+	return false
+}
+
+func renderDelimited(output io.Writer, rows []ValueRow, columns []string, delimiter rune) error {
+	if len(columns) == 0 {
+		columns = defaultDelimitedColumns
+	}
+
+	columns, err := normalizeDelimitedColumns(columns)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(output)
+	writer.Comma = delimiter
+
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		record := make([]string, len(columns))
+
+		for i, column := range columns {
+			record[i] = getDelimitedColumnValue(row, column)
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,60 @@
+package document
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderCSV(t *testing.T) {
+	yamlValues := parseYamlValues(`
+# -- Pod annotations, "quoted" for good measure
+annotations:
+  a: b
+  c: d
+# -- The replica count,
+# spread across zones
+replicas: 2
+	`)
+
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
+	require.NoError(t, err)
+
+	var output bytes.Buffer
+	err = renderDelimited(&output, valuesRows, nil, ',')
+	require.NoError(t, err)
+
+	const expected = `key,type,default,description
+annotations,object,"{""a"":""b"",""c"":""d""}","Pod annotations, ""quoted"" for good measure"
+replicas,int,2,"The replica count, spread across zones"
+`
+	assert.Equal(t, expected, output.String())
+}
+
+func TestRenderTSVWithColumns(t *testing.T) {
+	yamlValues := parseYamlValues(`
+# -- (int) Number of replicas
+replicas:
+image: nginx
+	`)
+
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
+	require.NoError(t, err)
+
+	var output bytes.Buffer
+	err = renderDelimited(&output, valuesRows, []string{"Default", "key", "line"}, '\t')
+	require.NoError(t, err)
+
+	assert.Equal(t, "default\tkey\tline\n\"\"\"nginx\"\"\"\timage\t3\nnil\treplicas\t2\n", output.String())
+}
+
+func TestRenderDelimitedUnknownColumn(t *testing.T) {
+	var output bytes.Buffer
+	err := renderDelimited(&output, []ValueRow{{Key: "a"}}, []string{"key", "flavor"}, ',')
+	assert.Error(t, err)
+
+	err = renderDelimited(&output, []ValueRow{}, []string{"key", "flavor"}, ',')
+	assert.Error(t, err)
+}
@@ -0,0 +1,178 @@
+package document
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kinds of change between two versions of a values file
+const (
+	AddedChange          = "added"
+	RemovedChange        = "removed"
+	RetypedChange        = "retyped"
+	ChangedDefaultChange = "changed-default"
+)
+
+// ValueChange is a documented key that differs between two versions of a values file. Keys whose type changed are only
+// reported as retyped, even though their default will have changed as well.
+type ValueChange struct {
+	Key         string `json:"key"`
+	Change      string `json:"change"`
+	Description string `json:"description,omitempty"`
+	OldType     string `json:"oldType,omitempty"`
+	NewType     string `json:"newType,omitempty"`
+	OldDefault  string `json:"oldDefault,omitempty"`
+	NewDefault  string `json:"newDefault,omitempty"`
+
+	oldRow ValueRow
+	newRow ValueRow
+}
+
+// ValuesDiff lists the changes between two versions of a values file, sorted by key
+type ValuesDiff struct {
+	From    string        `json:"from"`
+	To      string        `json:"to"`
+	Changes []ValueChange `json:"changes"`
+}
+
+func newValueChange(change string, oldRow ValueRow, newRow ValueRow) ValueChange {
+	description := newRow.Description
+	if description == "" {
+		description = oldRow.Description
+	}
+
+	valueChange := ValueChange{Key: newRow.Key, Change: change, Description: description, oldRow: oldRow, newRow: newRow}
+	if change != AddedChange {
+		valueChange.Key = oldRow.Key
+		valueChange.OldType = oldRow.Type
+		valueChange.OldDefault = fullDefault(oldRow)
+	}
+
+	if change != RemovedChange {
+		valueChange.NewType = newRow.Type
+		valueChange.NewDefault = fullDefault(newRow)
+	}
+
+	return valueChange
+}
+
+// DiffValues compares the rows of two versions of a values file, named from and to in the report
+func DiffValues(from string, oldValues *yaml.Node, to string, newValues *yaml.Node, options Options) (ValuesDiff, error) {
+	oldRows, err := ValueRows(oldValues, options)
+	if err != nil {
+		return ValuesDiff{}, fmt.Errorf("failed to read values of %s: %w", from, err)
+	}
+
+	newRows, err := ValueRows(newValues, options)
+	if err != nil {
+		return ValuesDiff{}, fmt.Errorf("failed to read values of %s: %w", to, err)
+	}
+
+	oldRowsByKey := getValueRowsByKey(oldRows)
+	newRowsByKey := getValueRowsByKey(newRows)
+	changes := make([]ValueChange, 0)
+
+	for _, oldRow := range oldRows {
+		newRow, ok := newRowsByKey[oldRow.Key]
+
+		switch {
+		case !ok:
+			changes = append(changes, newValueChange(RemovedChange, oldRow, ValueRow{}))
+		case oldRow.Type != newRow.Type:
+			changes = append(changes, newValueChange(RetypedChange, oldRow, newRow))
+		case fullDefault(oldRow) != fullDefault(newRow):
+			changes = append(changes, newValueChange(ChangedDefaultChange, oldRow, newRow))
+		}
+	}
+
+	for _, newRow := range newRows {
+		if _, ok := oldRowsByKey[newRow.Key]; !ok {
+			changes = append(changes, newValueChange(AddedChange, ValueRow{}, newRow))
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return ValuesDiff{From: from, To: to, Changes: changes}, nil
+}
+
+func (d ValuesDiff) changesOfKind(change string) []ValueChange {
+	changes := make([]ValueChange, 0)
+
+	for _, c := range d.Changes {
+		if c.Change == change {
+			changes = append(changes, c)
+		}
+	}
+
+	return changes
+}
+
+// RenderDiffJSON writes the diff as json, with defaults in their plain form rather than as markdown
+func RenderDiffJSON(output io.Writer, diff ValuesDiff) error {
+	encoder := json.NewEncoder(output)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(diff)
+}
+
+// Long defaults are truncated in the values table, upgrade notes need the full value to be of any use, so it's shown in a
+// pre element as in the details of the values table
+func escapeFullDefault(escapeFormat string, row ValueRow) string {
+	if row.LongDefault == "" {
+		return escapeDefault(escapeFormat, row.Default)
+	}
+
+	return `<pre lang="yaml">` + escapeCodeBlock(row.LongDefault) + "</pre>"
+}
+
+// RenderDiffMarkdown writes the diff as markdown upgrade notes, with a table for each kind of change
+func RenderDiffMarkdown(output io.Writer, diff ValuesDiff, escapeFormat string) error {
+	fmt.Fprintf(output, "## Values changes from %s to %s\n", diff.From, diff.To)
+
+	if len(diff.Changes) == 0 {
+		_, err := fmt.Fprint(output, "\nNo documented values changed.\n")
+		return err
+	}
+
+	sections := []struct {
+		change  string
+		title   string
+		columns string
+		row     func(c ValueChange) string
+	}{
+		{AddedChange, "Added", "| Key | Type | Default | Description |", func(c ValueChange) string {
+			return fmt.Sprintf("%s | %s", c.NewType, escapeFullDefault(escapeFormat, c.newRow))
+		}},
+		{RemovedChange, "Removed", "| Key | Type | Default | Description |", func(c ValueChange) string {
+			return fmt.Sprintf("%s | %s", c.OldType, escapeFullDefault(escapeFormat, c.oldRow))
+		}},
+		{RetypedChange, "Changed type", "| Key | Old type | New type | Description |", func(c ValueChange) string {
+			return fmt.Sprintf("%s | %s", c.OldType, c.NewType)
+		}},
+		{ChangedDefaultChange, "Changed default", "| Key | Old default | New default | Description |", func(c ValueChange) string {
+			return fmt.Sprintf("%s | %s", escapeFullDefault(escapeFormat, c.oldRow), escapeFullDefault(escapeFormat, c.newRow))
+		}},
+	}
+
+	for _, section := range sections {
+		changes := diff.changesOfKind(section.change)
+		if len(changes) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(output, "\n### %s\n\n%s\n|-----|-----|-----|-------------|\n", section.title, section.columns)
+		for _, c := range changes {
+			fmt.Fprintf(output, "| %s | %s | %s |\n", escapeKey(escapeFormat, c.Key), section.row(c), escapeDescription(escapeFormat, c.Description))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package document
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func parseValuesDocument(t *testing.T, values string) *yaml.Node {
+	var valuesData yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(strings.TrimSpace(values)), &valuesData))
+
+	return &valuesData
+}
+
+const oldDiffValues = `
+# -- Number of replicas
+replicas: 1
+# -- Image tag
+tag: "1.0"
+# -- Port to listen on
+port: "80"
+# -- Removed setting
+legacy: true
+`
+
+const newDiffValues = `
+# -- Number of replicas
+replicas: 1
+# -- Image tag
+tag: "1.1"
+# -- Port to listen on
+port: 80
+# -- Pod annotations
+annotations: {}
+`
+
+func TestDiffValues(t *testing.T) {
+	diff, err := DiffValues("v1", parseValuesDocument(t, oldDiffValues), "v2", parseValuesDocument(t, newDiffValues), Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "v1", diff.From)
+	assert.Equal(t, "v2", diff.To)
+
+	changes := make([][]string, 0)
+	for _, c := range diff.Changes {
+		changes = append(changes, []string{c.Key, c.Change, c.OldType, c.NewType, c.OldDefault, c.NewDefault})
+	}
+
+	assert.Equal(t, [][]string{
+		{"annotations", AddedChange, "", objectType, "", "{}"},
+		{"legacy", RemovedChange, boolType, "", "true", ""},
+		{"port", RetypedChange, stringType, intType, `"80"`, "80"},
+		{"tag", ChangedDefaultChange, stringType, stringType, `"1.0"`, `"1.1"`},
+	}, changes)
+}
+
+func TestRenderDiffMarkdown(t *testing.T) {
+	diff, err := DiffValues("v1", parseValuesDocument(t, oldDiffValues), "v2", parseValuesDocument(t, newDiffValues), Options{})
+	require.NoError(t, err)
+
+	var output bytes.Buffer
+	require.NoError(t, RenderDiffMarkdown(&output, diff, GitHubEscapeFormat))
+
+	assert.Equal(t, "## Values changes from v1 to v2\n\n"+
+		"### Added\n\n| Key | Type | Default | Description |\n|-----|-----|-----|-------------|\n"+
+		"| annotations | object | `{}` | Pod annotations |\n\n"+
+		"### Removed\n\n| Key | Type | Default | Description |\n|-----|-----|-----|-------------|\n"+
+		"| legacy | bool | `true` | Removed setting |\n\n"+
+		"### Changed type\n\n| Key | Old type | New type | Description |\n|-----|-----|-----|-------------|\n"+
+		"| port | string | int | Port to listen on |\n\n"+
+		"### Changed default\n\n| Key | Old default | New default | Description |\n|-----|-----|-----|-------------|\n"+
+		"| tag | `\"1.0\"` | `\"1.1\"` | Image tag |\n", output.String())
+}
+
+func TestRenderDiffMarkdownLongDefaults(t *testing.T) {
+	oldValues := parseValuesDocument(t, "# -- Volumes\nvolumes:\n  - name: data\n    size: 1Gi\n")
+	newValues := parseValuesDocument(t, "# -- Volumes\nvolumes:\n  - name: data\n    size: 2Gi\n")
+
+	diff, err := DiffValues("v1", oldValues, "v2", newValues, Options{DefaultMaxLength: 10})
+	require.NoError(t, err)
+
+	var output bytes.Buffer
+	require.NoError(t, RenderDiffMarkdown(&output, diff, GitHubEscapeFormat))
+
+	assert.Equal(t, "## Values changes from v1 to v2\n\n"+
+		"### Changed default\n\n| Key | Old default | New default | Description |\n|-----|-----|-----|-------------|\n"+
+		"| volumes | <pre lang=\"yaml\">- name: data<br>  size: 1Gi</pre> | <pre lang=\"yaml\">- name: data<br>  size: 2Gi</pre> | Volumes |\n", output.String())
+}
+
+func TestRenderDiffMarkdownWithoutChanges(t *testing.T) {
+	var output bytes.Buffer
+	require.NoError(t, RenderDiffMarkdown(&output, ValuesDiff{From: "a.yaml", To: "b.yaml"}, GitHubEscapeFormat))
+
+	assert.Equal(t, "## Values changes from a.yaml to b.yaml\n\nNo documented values changed.\n", output.String())
+}
+
+func TestRenderDiffJSON(t *testing.T) {
+	diff, err := DiffValues("v1", parseValuesDocument(t, oldDiffValues), "v2", parseValuesDocument(t, newDiffValues), Options{})
+	require.NoError(t, err)
+
+	var output bytes.Buffer
+	require.NoError(t, RenderDiffJSON(&output, diff))
+
+	assert.Contains(t, output.String(), `"from": "v1"`)
+	assert.Contains(t, output.String(), `{
+      "key": "legacy",
+      "change": "removed",
+      "description": "Removed setting",
+      "oldType": "bool",
+      "oldDefault": "true"
+    }`)
+}
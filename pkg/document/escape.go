@@ -0,0 +1,235 @@
+package document
+
+import (
+	"bytes"
+	"html"
+	"strings"
+	"text/template"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	GitHubEscapeFormat     = "github"
+	CommonMarkEscapeFormat = "commonmark"
+	HTMLEscapeFormat       = "html"
+	AsciiDocEscapeFormat   = "asciidoc"
+)
+
+var lineBreakReplacer = strings.NewReplacer("\r\n", "\n")
+
+// Everything that could start or end markdown inline markup, keys are literal text and must render as such. Brackets
+// are left alone, list indices can't form a link on their own and escaping them makes keys hard to read in the source
+var markdownKeyEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "<", `\<`, ">", `\>`, "|", `\|`,
+)
+
+// Descriptions may contain intentional markdown such as links, so only what would break out of a table cell or inject
+// html is escaped. Entities aren't decoded in code spans, which are escaped by markdownCodeSpanEscaper instead.
+var markdownDescriptionEscaper = strings.NewReplacer("|", `\|`, "<", "&lt;", ">", "&gt;", "\n", "<br>")
+var markdownCodeSpanEscaper = strings.NewReplacer("|", `\|`, "\n", " ")
+
+// Pipes are harmless in html, but encoding them keeps html output usable inside markdown tables as well
+var htmlTextEscaper = strings.NewReplacer("\n", "<br>", "|", "&#124;")
+var asciiDocEscaper = strings.NewReplacer("|", `\|`, "\n", " +\n")
+
+// Long defaults are shown in a pre element inside of table cells, where markdown inline markup is still parsed and
+// line breaks would end the table row
+var inlineCodeBlockEscaper = strings.NewReplacer(
+	"\n", "<br>", "|", "&#124;", "*", "&#42;", "_", "&#95;", "`", "&#96;", "[", "&#91;", "]", "&#93;", `\`, "&#92;",
+)
+
+func escapeCodeBlock(code string) string {
+	return inlineCodeBlockEscaper.Replace(html.EscapeString(lineBreakReplacer.Replace(code)))
+}
+
+func isKnownEscapeFormat(format string) bool {
+	switch format {
+	case GitHubEscapeFormat, CommonMarkEscapeFormat, HTMLEscapeFormat, AsciiDocEscapeFormat:
+		return true
+	}
+
+	return false
+}
+
+func escapeKey(format string, key string) string {
+	key = lineBreakReplacer.Replace(key)
+
+	switch format {
+	case HTMLEscapeFormat:
+		return htmlTextEscaper.Replace(html.EscapeString(key))
+	case AsciiDocEscapeFormat:
+		return asciiDocEscaper.Replace(key)
+	}
+
+	return markdownKeyEscaper.Replace(strings.ReplaceAll(key, "\n", " "))
+}
+
+func escapeDescription(format string, description string) string {
+	description = lineBreakReplacer.Replace(description)
+
+	switch format {
+	case HTMLEscapeFormat:
+		return htmlTextEscaper.Replace(html.EscapeString(description))
+	case AsciiDocEscapeFormat:
+		return asciiDocEscaper.Replace(description)
+	}
+
+	return replaceOutsideCodeSpans(description, markdownDescriptionEscaper.Replace, func(codeSpan string) string {
+		// Table cells are split on unescaped pipes before code spans are parsed, see escapeDefault
+		if format == CommonMarkEscapeFormat {
+			return strings.ReplaceAll(codeSpan, "\n", " ")
+		}
+
+		return markdownCodeSpanEscaper.Replace(codeSpan)
+	})
+}
+
+// A code span starts with a backtick string and ends with the next backtick string of the same length, backticks that
+// are escaped or never closed are literal text
+func replaceOutsideCodeSpans(text string, replaceText func(string) string, replaceCodeSpan func(string) string) string {
+	var result strings.Builder
+	textStart := 0
+
+	for i := 0; i < len(text); {
+		if text[i] != '`' || (i > 0 && text[i-1] == '\\') {
+			i++
+			continue
+		}
+
+		fenceLength := backtickRunLength(text, i)
+		end := -1
+		for j := i + fenceLength; j < len(text); {
+			if text[j] != '`' {
+				j++
+				continue
+			}
+
+			runLength := backtickRunLength(text, j)
+			if runLength == fenceLength {
+				end = j + runLength
+				break
+			}
+
+			j += runLength
+		}
+
+		if end < 0 {
+			i += fenceLength
+			continue
+		}
+
+		result.WriteString(replaceText(text[textStart:i]))
+		result.WriteString(replaceCodeSpan(text[i:end]))
+		i, textStart = end, end
+	}
+
+	result.WriteString(replaceText(text[textStart:]))
+	return result.String()
+}
+
+func backtickRunLength(text string, start int) int {
+	end := start
+	for end < len(text) && text[end] == '`' {
+		end++
+	}
+
+	return end - start
+}
+
+// A markdown code span must be delimited by a backtick string longer than any backtick string in its contents, and
+// padded with a space when the contents start or end with a backtick
+func markdownCodeSpan(code string) string {
+	longestRun, currentRun := 0, 0
+
+	for _, c := range code {
+		if c == '`' {
+			currentRun++
+			if currentRun > longestRun {
+				longestRun = currentRun
+			}
+		} else {
+			currentRun = 0
+		}
+	}
+
+	fence := strings.Repeat("`", longestRun+1)
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+		return fence + " " + code + " " + fence
+	}
+
+	return fence + code + fence
+}
+
+// Defaults generated from the values file are code spans, custom defaults from @default comments are treated as
+// descriptions
+func escapeDefault(format string, defaultValue string) string {
+	if !isCodeSpan(defaultValue) {
+		return escapeDescription(format, defaultValue)
+	}
+
+	code := strings.ReplaceAll(lineBreakReplacer.Replace(plainDefault(defaultValue)), "\n", " ")
+
+	switch format {
+	case HTMLEscapeFormat:
+		return "<code>" + htmlTextEscaper.Replace(html.EscapeString(code)) + "</code>"
+	case AsciiDocEscapeFormat:
+		return "`+" + strings.ReplaceAll(code, "|", `\|`) + "+`"
+	case CommonMarkEscapeFormat:
+		return markdownCodeSpan(code)
+	}
+
+	// Table cells are split on unescaped pipes before code spans are parsed, so github needs them escaped even here
+	return markdownCodeSpan(strings.ReplaceAll(code, "|", `\|`))
+}
+
+// Defaults in yaml snippets are strings, single quoted when they fit on one line and otherwise a literal block, whose
+// lines are indented to sit below a key indented by indent spaces
+func quoteYamlDefault(defaultValue string, indent int) (string, error) {
+	value := plainDefault(defaultValue)
+	style := yaml.SingleQuotedStyle
+	if strings.Contains(value, "\n") {
+		style = yaml.LiteralStyle
+	}
+
+	var output bytes.Buffer
+	encoder := yaml.NewEncoder(&output)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Style: style, Value: value}); err != nil {
+		return "", err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	for i := 1; i < len(lines); i++ {
+		if lines[i] != "" {
+			lines[i] = strings.Repeat(" ", indent) + lines[i]
+		}
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+func getEscapeFuncMap(escapeFormat string) template.FuncMap {
+	if escapeFormat == "" {
+		escapeFormat = GitHubEscapeFormat
+	} else if !isKnownEscapeFormat(escapeFormat) {
+		log.Infof("Invalid escape format `%s`, defaulting to %s", escapeFormat, GitHubEscapeFormat)
+		escapeFormat = GitHubEscapeFormat
+	}
+
+	return template.FuncMap{
+		"escapeKey":           func(key string) string { return escapeKey(escapeFormat, key) },
+		"escapeDefault":       func(defaultValue string) string { return escapeDefault(escapeFormat, defaultValue) },
+		"escapeDescription":   func(description string) string { return escapeDescription(escapeFormat, description) },
+		"escapeCodeBlock":     escapeCodeBlock,
+		"escapeKeyAs":         escapeKey,
+		"escapeDefaultAs":     escapeDefault,
+		"escapeDescriptionAs": escapeDescription,
+		"quoteYamlDefault":    quoteYamlDefault,
+	}
+}
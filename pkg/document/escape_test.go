@@ -0,0 +1,43 @@
+package document
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeKey(t *testing.T) {
+	assert.Equal(t, `service.annotations."a\_b\*c"`, escapeKey(GitHubEscapeFormat, `service.annotations."a_b*c"`))
+	assert.Equal(t, `list[0].name\_override`, escapeKey(CommonMarkEscapeFormat, "list[0].name_override"))
+	assert.Equal(t, `a&lt;b&gt;&#124;c`, escapeKey(HTMLEscapeFormat, "a<b>|c"))
+	assert.Equal(t, `a\|b`, escapeKey(AsciiDocEscapeFormat, "a|b"))
+}
+
+func TestEscapeDescription(t *testing.T) {
+	description := "See [docs](https://example.com) | <b>bold</b>\nnext line"
+
+	assert.Equal(t, `See [docs](https://example.com) \| &lt;b&gt;bold&lt;/b&gt;<br>next line`, escapeDescription(GitHubEscapeFormat, description))
+	assert.Equal(t, `See [docs](https://example.com) \| &lt;b&gt;bold&lt;/b&gt;<br>next line`, escapeDescription(CommonMarkEscapeFormat, description))
+	assert.Equal(t, `See [docs](https://example.com) &#124; &lt;b&gt;bold&lt;/b&gt;<br>next line`, escapeDescription(HTMLEscapeFormat, description))
+	assert.Equal(t, "See [docs](https://example.com) \\| <b>bold</b> +\nnext line", escapeDescription(AsciiDocEscapeFormat, description))
+}
+
+func TestEscapeDescriptionCodeSpans(t *testing.T) {
+	description := "Set to `<name>` of the secret, `a|b` <i>or</i> ``x`y``"
+
+	assert.Equal(t, "Set to `<name>` of the secret, `a\\|b` &lt;i&gt;or&lt;/i&gt; ``x`y``", escapeDescription(GitHubEscapeFormat, description))
+	assert.Equal(t, "Set to `<name>` of the secret, `a|b` &lt;i&gt;or&lt;/i&gt; ``x`y``", escapeDescription(CommonMarkEscapeFormat, description))
+	assert.Equal(t, "an `unclosed &lt;span&gt;", escapeDescription(GitHubEscapeFormat, "an `unclosed <span>"))
+	assert.Equal(t, "\\`&lt;b&gt;\\`", escapeDescription(GitHubEscapeFormat, "\\`<b>\\`"))
+	assert.Equal(t, "`a b`<br>next", escapeDescription(GitHubEscapeFormat, "`a\nb`\nnext"))
+}
+
+func TestEscapeDefault(t *testing.T) {
+	assert.Equal(t, "`\"a\\|b\"`", escapeDefault(GitHubEscapeFormat, "`\"a|b\"`"))
+	assert.Equal(t, "`\"a|b\"`", escapeDefault(CommonMarkEscapeFormat, "`\"a|b\"`"))
+	assert.Equal(t, "``\"`cmd`\"``", escapeDefault(GitHubEscapeFormat, "`\"`cmd`\"`"))
+	assert.Equal(t, "`` `cmd` ``", escapeDefault(GitHubEscapeFormat, "``cmd``"))
+	assert.Equal(t, "<code>&#34;&lt;html&gt;&#34;</code>", escapeDefault(HTMLEscapeFormat, "`\"<html>\"`"))
+	assert.Equal(t, "`+\"a\\|b\"+`", escapeDefault(AsciiDocEscapeFormat, "`\"a|b\"`"))
+	assert.Equal(t, "the chart computes it \\| &lt;somehow&gt;", escapeDefault(GitHubEscapeFormat, "the chart computes it | <somehow>"))
+}
@@ -2,20 +2,23 @@ package document
 
 import (
 	"bytes"
+	"fmt"
+	"io"
 	"os"
 	"regexp"
 
 	log "github.com/sirupsen/logrus"
-	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+	"github.com/theEndBeta/yaml-docs/pkg/helm"
 )
 
-func getOutputFile(dryRun bool) (*os.File, error) {
+func getOutputFile(outputFile string, dryRun bool) (*os.File, error) {
 	if dryRun {
 		return os.Stdout, nil
 	}
 
-	outputFile := viper.GetString("output-file")
 	f, err := os.Create(outputFile)
 
 	if err != nil {
@@ -25,43 +28,239 @@ func getOutputFile(dryRun bool) (*os.File, error) {
 	return f, err
 }
 
-func PrintDocumentation(valuesData *yaml.Node, templateFiles []string, dryRun bool, yamlDocsVersion string) {
-	log.Infof("Generating README Documentation")
+// DefaultOutputFile returns the file that documentation in the output format of options is written to when
+// options.OutputFile is empty. Only markdown documentation is written to README.md.
+func DefaultOutputFile(options Options) string {
+	switch options.OutputFormat {
+	case CSVOutputFormat:
+		return "values.csv"
+	case TSVOutputFormat:
+		return "values.tsv"
+	case ManOutputFormat:
+		chart, _ := getChartMetadata(options)
+		return getManPageName(options, chart) + ".5"
+	case ConfluenceOutputFormat:
+		return "values.confluence.xhtml"
+	}
+
+	return "README.md"
+}
+
+// TemplateError is returned when the documentation templates can't be parsed or executed
+type TemplateError struct {
+	Err error
+}
+
+func (e *TemplateError) Error() string {
+	return fmt.Sprintf("error in documentation template: %s", e.Err)
+}
+
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
 
-	documentationTemplate, err := newDocumentationTemplate(templateFiles)
+// StrictError is returned in strict mode when any warnings were found
+type StrictError struct {
+	Warnings []diagnostic.Diagnostic
+}
+
+func (e *StrictError) Error() string {
+	return fmt.Sprintf("%d warning(s) found in strict mode, first: %s", len(e.Warnings), e.Warnings[0])
+}
+
+// Generate parses the values files and renders their documentation in the configured output format. It holds no state
+// between calls and is safe for concurrent use.
+func Generate(options Options) ([]byte, error) {
+	var generate func(Options) ([]byte, []diagnostic.Diagnostic, error)
+
+	switch options.Profile {
+	case CRDProfile:
+		generate = generateCustomResources
+	case ActionProfile:
+		generate = generateAction
+	case ComposeProfile:
+		generate = generateCompose
+	case "", ValuesProfile:
+	default:
+		log.Infof("Invalid profile `%s`, defaulting to %s", options.Profile, ValuesProfile)
+	}
 
+	if generate != nil {
+		output, warnings, err := generate(options)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := options.reportWarnings(warnings); err != nil {
+			return nil, err
+		}
+
+		return output, nil
+	}
+
+	valuesData, warnings, err := helm.ParseValuesFS(options.FileSystem(), options.ValuesFiles)
 	if err != nil {
-		log.Warnf("Error generating gotemplates: %s", err)
-		return
+		return nil, err
 	}
 
-	chartTemplateDataObject, err := getChartTemplateData(valuesData, yamlDocsVersion)
+	output, templateWarnings, err := generateFromValues(valuesData, options)
 	if err != nil {
-		log.Warnf("Error generating template data: %s", err)
-		return
+		return nil, err
+	}
+
+	if err := options.reportWarnings(append(warnings, templateWarnings...)); err != nil {
+		return nil, err
 	}
 
-	outputFile, err := getOutputFile(dryRun)
+	return output, nil
+}
+
+// GenerateFromValues renders documentation for already parsed values, ignoring options.ValuesFiles
+func GenerateFromValues(valuesData *yaml.Node, options Options) ([]byte, error) {
+	output, warnings, err := generateFromValues(valuesData, options)
 	if err != nil {
-		log.Warnf("Could not open chart README file %s", err)
-		return
+		return nil, err
 	}
 
-	if !dryRun {
-		defer outputFile.Close()
+	if err := options.reportWarnings(warnings); err != nil {
+		return nil, err
 	}
 
+	return output, nil
+}
+
+func generateFromValues(valuesData *yaml.Node, options Options) ([]byte, []diagnostic.Diagnostic, error) {
+	templateData, err := getTemplateData(valuesData, options)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error generating template data: %w", err)
+	}
+
+	chart, warnings := getChartMetadata(options)
+	templateData.Chart = chart
+
+	// Only generation reads the history, rows built for lint, diff or the language server never show it
+	if options.GitHistory && len(options.ValuesFiles) > 0 {
+		warnings = append(warnings, addValueRowsHistory(templateData.Values, options.ValuesFiles[0], options)...)
+	}
+
+	if options.DocumentSubcharts {
+		var subchartWarnings []diagnostic.Diagnostic
+		templateData.Values, subchartWarnings, err = addSubchartValueRows(templateData.Values, valuesData, chart, options)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error generating template data: %w", err)
+		}
+
+		warnings = append(warnings, subchartWarnings...)
+
+		// Subchart rows are sorted in with the others, which moves the rows that tree nodes refer to. The tree follows the
+		// values file, so stays empty for an empty one.
+		if !isEmptyValuesDocument(valuesData) {
+			templateData.ValuesTree = getValuesTree(valuesData.Content[0], templateData.Values, options)
+		}
+	}
+
+	output, formatWarnings, err := renderTemplateData(templateData, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return output, append(warnings, formatWarnings...), nil
+}
+
+// Formats other than markdown render the rows of the values table only
+func renderTemplateData(templateData TemplateData, options Options) ([]byte, []diagnostic.Diagnostic, error) {
 	var output bytes.Buffer
-	err = documentationTemplate.Execute(&output, chartTemplateDataObject)
+	var warnings []diagnostic.Diagnostic
+	var err error
+
+	switch options.OutputFormat {
+	case CSVOutputFormat:
+		err = renderDelimited(&output, templateData.Values, options.Columns, ',')
+	case TSVOutputFormat:
+		err = renderDelimited(&output, templateData.Values, options.Columns, '\t')
+	case ManOutputFormat:
+		manPageName := getManPageName(options, templateData.Chart)
+		err = renderManPage(&output, templateData.Values, manPageName, options.YamlDocsVersion)
+	case ConfluenceOutputFormat:
+		err = renderConfluence(&output, templateData.Values, options.DefaultFormat)
+	default:
+		if options.OutputFormat != "" && options.OutputFormat != MarkdownOutputFormat {
+			log.Infof("Invalid output format `%s`, defaulting to %s", options.OutputFormat, MarkdownOutputFormat)
+		}
+
+		warnings, err = renderMarkdown(&output, templateData, options)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return output.Bytes(), warnings, nil
+}
+
+// Profiles documenting several tables of values, such as the fields of each custom resource or the environment of each
+// compose service, have no single table to render in the other output formats. Their rows are in the profile specific
+// fields of templateData.
+func renderProfileMarkdown(templateData TemplateData, options Options) ([]byte, []diagnostic.Diagnostic, error) {
+	if options.OutputFormat != "" && options.OutputFormat != MarkdownOutputFormat {
+		return nil, nil, fmt.Errorf("output format %s is not supported by the %s profile", options.OutputFormat, options.Profile)
+	}
+
+	templateData.YamlDocsVersion = options.YamlDocsVersion
+	templateData.Values = make([]ValueRow, 0)
+	templateData.ValuesTree = make([]ValueTreeNode, 0)
+	templateData.LongDefaultStyle = options.LongDefaultStyle
+
+	var output bytes.Buffer
+	warnings, err := renderMarkdown(&output, templateData, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return output.Bytes(), warnings, nil
+}
+
+func renderMarkdown(output io.Writer, templateData TemplateData, options Options) ([]diagnostic.Diagnostic, error) {
+	documentationTemplate, warnings, err := newDocumentationTemplate(options.FileSystem(), options.TemplateFiles, options.Profile, options.EscapeFormat)
 	if err != nil {
-		log.Warnf("Error generating documentation: %s", err)
+		return nil, err
 	}
 
-	output = applyMarkDownFormat(output)
-	_, err = output.WriteTo(outputFile)
+	var markdown bytes.Buffer
+	err = documentationTemplate.Execute(&markdown, templateData)
+	if err != nil {
+		return nil, &TemplateError{Err: err}
+	}
+
+	markdown = applyMarkDownFormat(markdown)
+	_, err = markdown.WriteTo(output)
+	return warnings, err
+}
+
+// PrintDocumentation generates documentation and writes it to options.OutputFile, or to stdout on dry runs
+func PrintDocumentation(options Options, dryRun bool) error {
+	log.Infof("Generating README Documentation")
+
+	output, err := Generate(options)
 	if err != nil {
-		log.Warnf("Error generating documentation [markdown]: %s", err)
+		return err
 	}
+
+	if options.OutputFile == "" {
+		options.OutputFile = DefaultOutputFile(options)
+	}
+
+	outputFile, err := getOutputFile(options.OutputFile, dryRun)
+	if err != nil {
+		return fmt.Errorf("could not open documentation file %s: %w", options.OutputFile, err)
+	}
+
+	if !dryRun {
+		defer outputFile.Close()
+	}
+
+	_, err = outputFile.Write(output)
+	return err
 }
 
 func applyMarkDownFormat(output bytes.Buffer) bytes.Buffer {
@@ -0,0 +1,242 @@
+package document
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+	"github.com/theEndBeta/yaml-docs/pkg/helm"
+)
+
+func TestGenerate(t *testing.T) {
+	output, err := Generate(Options{
+		ValuesFiles:   []string{"testdata/values.yaml"},
+		TemplateFiles: []string{"testdata/nonexistent.md.gotmpl"},
+	})
+
+	const expected = "\n## Values\n\n" +
+		"| Key | Type | Default | Description |\n" +
+		"|-----|------|---------|-------------|\n" +
+		"| image.repository | string | `\"nginx\"` |  |\n" +
+		"| replicas | int | `2` | Number of replicas |\n\n"
+
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(output))
+}
+
+func TestGenerateFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"charts/app/values.yaml":      {Data: []byte("# -- Name of the app\nname: app\n")},
+		"charts/app/README.md.gotmpl": {Data: []byte(`{{ range .Values }}{{ .Key }}: {{ .Description }}{{ end }}`)},
+	}
+
+	output, err := Generate(Options{
+		FS:            fsys,
+		ValuesFiles:   []string{"charts/app/values.yaml"},
+		TemplateFiles: []string{"charts/app/README.md.gotmpl"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "name: Name of the app", string(output))
+}
+
+func TestGenerateConcurrently(t *testing.T) {
+	formats := []string{MarkdownOutputFormat, CSVOutputFormat, ManOutputFormat, ConfluenceOutputFormat}
+	outputs := make([][]byte, len(formats))
+	errs := make([]error, len(formats))
+	waitGroup := sync.WaitGroup{}
+
+	for i, format := range formats {
+		waitGroup.Add(1)
+
+		go func(i int, format string) {
+			defer waitGroup.Done()
+			outputs[i], errs[i] = Generate(Options{ValuesFiles: []string{"testdata/values.yaml"}, OutputFormat: format})
+		}(i, format)
+	}
+
+	waitGroup.Wait()
+
+	for i, format := range formats {
+		require.NoError(t, errs[i])
+
+		expected, err := Generate(Options{ValuesFiles: []string{"testdata/values.yaml"}, OutputFormat: format})
+		require.NoError(t, err)
+		assert.Equal(t, string(expected), string(outputs[i]))
+	}
+}
+
+func TestGenerateStrict(t *testing.T) {
+	fsys := fstest.MapFS{
+		"values.yaml": {Data: []byte("# -- Name\n# @default: app\nname: app\n")},
+	}
+
+	warnings := make([]diagnostic.Diagnostic, 0)
+	options := Options{
+		FS:          fsys,
+		ValuesFiles: []string{"values.yaml"},
+		OnWarning:   func(d diagnostic.Diagnostic) { warnings = append(warnings, d) },
+	}
+
+	_, err := Generate(options)
+	require.NoError(t, err)
+	assert.Len(t, warnings, 1)
+
+	options.Strict = true
+	_, err = Generate(options)
+
+	var strictErr *StrictError
+	require.True(t, errors.As(err, &strictErr))
+	assert.Equal(t, 2, strictErr.Warnings[0].Line)
+}
+
+func TestGenerateErrors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"invalid.yaml":     {Data: []byte("a: [\n")},
+		"README.md.gotmpl": {Data: []byte("{{ .Nope }")},
+	}
+
+	_, err := Generate(Options{FS: fsys, ValuesFiles: []string{"missing.yaml"}})
+	var fileErr *helm.FileError
+	assert.True(t, errors.As(err, &fileErr))
+
+	_, err = Generate(Options{FS: fsys, ValuesFiles: []string{"invalid.yaml"}})
+	var parseErr *helm.ParseError
+	assert.True(t, errors.As(err, &parseErr))
+
+	fsys["values.yaml"] = &fstest.MapFile{Data: []byte("a: 1\n")}
+	_, err = Generate(Options{FS: fsys, ValuesFiles: []string{"values.yaml"}, TemplateFiles: []string{"README.md.gotmpl"}})
+	var templateErr *TemplateError
+	assert.True(t, errors.As(err, &templateErr))
+}
+
+func TestGenerateChartMetadata(t *testing.T) {
+	fsys := fstest.MapFS{
+		"chart/Chart.yaml": {Data: []byte(`apiVersion: v2
+name: app
+description: An app
+version: 1.0.0-rc_1
+type: application
+home: https://example.com
+maintainers:
+  - name: Jane Doe
+    email: jane@example.com
+sources: ["https://example.com/src"]
+kubeVersion: ">=1.18"
+`)},
+		"chart/values.yaml":      {Data: []byte("# -- Number of replicas\nreplicas: 2\n")},
+		"chart/README.md.gotmpl": {Data: []byte(`{{ template "chart.name" . }} {{ template "chart.version" . }} {{ template "chart.badgesSection" . }}`)},
+		"chart/SUMMARY.md.gotmpl": {Data: []byte(`{{ template "chart.summarySection" . }}
+{{ template "docs.valuesSection" . }}
+
+{{ template "yaml-docs.versionFooter" . }}
+`)},
+	}
+
+	output, err := Generate(Options{
+		FS:            fsys,
+		ValuesFiles:   []string{"chart/values.yaml"},
+		TemplateFiles: []string{"chart/README.md.gotmpl"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "app 1.0.0-rc_1 "+
+		"![Version: 1.0.0-rc_1](https://img.shields.io/badge/Version-1.0.0--rc__1-informational?style=flat-square) "+
+		"![Type: application](https://img.shields.io/badge/Type-application-informational?style=flat-square) ", string(output))
+
+	output, err = Generate(Options{
+		FS:            fsys,
+		ValuesFiles:   []string{"chart/values.yaml"},
+		TemplateFiles: []string{"chart/SUMMARY.md.gotmpl"},
+	})
+
+	const expected = "# app\n\n" +
+		"![Version: 1.0.0-rc_1](https://img.shields.io/badge/Version-1.0.0--rc__1-informational?style=flat-square) " +
+		"![Type: application](https://img.shields.io/badge/Type-application-informational?style=flat-square)\n\n" +
+		"An app\n\n" +
+		"**Homepage:** <https://example.com>\n\n" +
+		"## Maintainers\n\n" +
+		"| Name | Email | Url |\n" +
+		"| ---- | ------ | --- |\n" +
+		"| Jane Doe | jane@example.com |  |\n\n" +
+		"## Source Code\n\n" +
+		"* <https://example.com/src>\n\n" +
+		"## Requirements\n\n" +
+		"Kubernetes: `>=1.18`\n\n" +
+		"## Values\n\n" +
+		"| Key | Type | Default | Description |\n" +
+		"|-----|------|---------|-------------|\n" +
+		"| replicas | int | `2` | Number of replicas |\n\n"
+
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(output))
+
+	// The default template only documents the values, even next to a Chart.yaml
+	output, err = Generate(Options{FS: fsys, ValuesFiles: []string{"chart/values.yaml"}})
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(output), "\n## Values\n"))
+}
+
+func TestGenerateInvalidChartMetadata(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Chart.yaml":  {Data: []byte("apiVersion: v3\nname: app\n")},
+		"values.yaml": {Data: []byte("replicas: 2\n")},
+	}
+
+	warnings := make([]diagnostic.Diagnostic, 0)
+	output, err := Generate(Options{
+		FS:          fsys,
+		ValuesFiles: []string{"values.yaml"},
+		OnWarning:   func(d diagnostic.Diagnostic) { warnings = append(warnings, d) },
+	})
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(output), "\n## Values\n"))
+	require.Len(t, warnings, 1)
+	assert.Equal(t, diagnostic.InvalidChartMetadataRule, warnings[0].Rule)
+	assert.Equal(t, "Chart.yaml", warnings[0].File)
+}
+
+func TestGenerateChartDependencies(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Chart.yaml": {Data: []byte(`apiVersion: v2
+name: umbrella
+dependencies:
+  - name: redis
+    alias: cache
+    version: "^17.0.0 || ^18.0.0"
+    repository: https://charts.bitnami.com/bitnami
+    condition: cache.enabled
+`)},
+		"Chart.lock":       {Data: []byte("dependencies:\n- name: redis\n  repository: https://charts.bitnami.com/bitnami\n  version: 18.1.0\n")},
+		"values.yaml":      {Data: []byte("cache:\n  enabled: true\n")},
+		"README.md.gotmpl": {Data: []byte(`{{ template "chart.dependenciesSection" . }}`)},
+	}
+
+	output, err := Generate(Options{FS: fsys, ValuesFiles: []string{"values.yaml"}, TemplateFiles: []string{"README.md.gotmpl"}})
+
+	const expected = "## Dependencies\n\n" +
+		"| Name | Repository | Version | Resolved Version | Alias | Condition |\n" +
+		"|------|------------|---------|------------------|-------|-----------|\n" +
+		"| redis | https://charts.bitnami.com/bitnami | ^17.0.0 \\|\\| ^18.0.0 | 18.1.0 | cache | cache.enabled |"
+
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(output))
+}
+
+func TestDefaultOutputFile(t *testing.T) {
+	assert.Equal(t, "README.md", DefaultOutputFile(Options{}))
+	assert.Equal(t, "README.md", DefaultOutputFile(Options{OutputFormat: MarkdownOutputFormat}))
+	assert.Equal(t, "values.csv", DefaultOutputFile(Options{OutputFormat: CSVOutputFormat}))
+	assert.Equal(t, "values.tsv", DefaultOutputFile(Options{OutputFormat: TSVOutputFormat}))
+	assert.Equal(t, "values.confluence.xhtml", DefaultOutputFile(Options{OutputFormat: ConfluenceOutputFormat}))
+	assert.Equal(t, "my-tool.5", DefaultOutputFile(Options{OutputFormat: ManOutputFormat, ValuesFiles: []string{"git:v1:config/my-tool/values.yaml"}}))
+	assert.Equal(t, "tool.5", DefaultOutputFile(Options{OutputFormat: ManOutputFormat, ManPageName: "tool"}))
+}
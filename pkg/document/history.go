@@ -0,0 +1,108 @@
+package document
+
+import (
+	"fmt"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+	"github.com/theEndBeta/yaml-docs/pkg/helm"
+	"github.com/theEndBeta/yaml-docs/pkg/util"
+)
+
+// The history of a key across the tagged versions of the values file
+type valueHistory struct {
+	since        string
+	changedIn    string
+	lastDefault  string
+	foundDefault bool
+}
+
+// Walks the tags of the repository containing the values file from the oldest version to the newest, noting the first
+// tag that contained each key and the last tag that changed its default. Only tags reachable from the documented
+// revision are walked, HEAD unless the values file is read from git. Keys added since the last tag have no history.
+func getValuesHistory(valuesFile string, options Options) (map[string]*valueHistory, error) {
+	revision := "HEAD"
+	if gitRevision, path, isGitPath := util.ParseGitPath(valuesFile); isGitPath {
+		revision, valuesFile = gitRevision, path
+	}
+
+	tags, err := util.ListGitTags(filepath.Dir(valuesFile), revision)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make(map[string]*valueHistory)
+
+	for _, tag := range tags {
+		contents, err := util.ReadGitFile(tag, valuesFile)
+		if err != nil {
+			log.Debugf("Skipping tag %s in the history of %s: %s", tag, valuesFile, err)
+			continue
+		}
+
+		valuesData, _, err := helm.ParseValuesContents(valuesFile, contents)
+		if err != nil {
+			log.Debugf("Skipping tag %s in the history of %s: %s", tag, valuesFile, err)
+			continue
+		}
+
+		rows, err := ValueRows(valuesData, options)
+		if err != nil {
+			log.Debugf("Skipping tag %s in the history of %s: %s", tag, valuesFile, err)
+			continue
+		}
+
+		for key := range KeyPaths(valuesData) {
+			if _, ok := history[key]; !ok {
+				history[key] = &valueHistory{since: tag}
+			}
+		}
+
+		for _, row := range rows {
+			h, ok := history[row.Key]
+			if !ok {
+				continue
+			}
+
+			defaultValue := fullDefault(row)
+
+			if h.foundDefault && defaultValue != h.lastDefault {
+				h.changedIn = tag
+			}
+
+			h.lastDefault = defaultValue
+			h.foundDefault = true
+		}
+	}
+
+	return history, nil
+}
+
+// The history is only read from the host filesystem, values files of any other filesystem aren't in a git repository
+func addValueRowsHistory(rows []ValueRow, valuesFile string, options Options) []diagnostic.Diagnostic {
+	if !util.IsHostFileSystem(options.FileSystem()) {
+		log.Debugf("Skipping the git history of %s, it isn't read from the host filesystem", valuesFile)
+		return nil
+	}
+
+	history, err := getValuesHistory(valuesFile, options)
+	if err != nil {
+		return []diagnostic.Diagnostic{{
+			File:     valuesFile,
+			Rule:     diagnostic.GitHistoryRule,
+			Severity: diagnostic.SeverityWarning,
+			Message:  fmt.Sprintf("failed to read the git history, rendering without it: %s", err),
+		}}
+	}
+
+	for i := range rows {
+		if h, ok := history[rows[i].Key]; ok {
+			rows[i].Since = h.since
+			rows[i].ChangedIn = h.changedIn
+		}
+	}
+
+	return nil
+}
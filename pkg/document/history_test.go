@@ -0,0 +1,134 @@
+package document
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+	"github.com/theEndBeta/yaml-docs/pkg/helm"
+	"github.com/theEndBeta/yaml-docs/pkg/util"
+)
+
+func runGit(t *testing.T, dir string, args ...string) {
+	command := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	command.Dir = dir
+
+	output, err := command.CombinedOutput()
+	require.NoError(t, err, string(output))
+}
+
+func TestValueRowsHistory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	valuesFile := filepath.Join(dir, "values.yaml")
+	runGit(t, dir, "init", "-q")
+
+	versions := []struct {
+		tag    string
+		values string
+	}{
+		{"v1.9.0", "# -- Replicas\nreplicas: 1\n"},
+		{"v1.10.0", "# -- Replicas\nreplicas: 2\n# -- Image\nimage: nginx\n"},
+		{"v1.11.0", "# -- Replicas\nreplicas: 2\n# -- Image\nimage: nginx\n"},
+	}
+
+	for _, version := range versions {
+		require.NoError(t, os.WriteFile(valuesFile, []byte(version.values), 0644))
+		runGit(t, dir, "add", "-A")
+		runGit(t, dir, "commit", "-q", "--allow-empty", "-m", version.tag)
+		runGit(t, dir, "tag", version.tag)
+	}
+
+	require.NoError(t, os.WriteFile(valuesFile, []byte("# -- Replicas\nreplicas: 2\n# -- Image\nimage: nginx\n# -- Port\nport: 80\n"), 0644))
+
+	options := Options{ValuesFiles: []string{valuesFile}, GitHistory: true}
+	valuesData, err := parseValuesFileForTest(valuesFile)
+	require.NoError(t, err)
+
+	// Rows built outside of generation have no history
+	rows, err := ValueRows(valuesData, options)
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, "", rows[2].Since)
+
+	assert.Empty(t, addValueRowsHistory(rows, valuesFile, options))
+
+	assert.Equal(t, "image", rows[0].Key)
+	assert.Equal(t, "v1.10.0", rows[0].Since)
+	assert.Equal(t, "", rows[0].ChangedIn)
+
+	assert.Equal(t, "port", rows[1].Key)
+	assert.Equal(t, "", rows[1].Since)
+
+	assert.Equal(t, "replicas", rows[2].Key)
+	assert.Equal(t, "v1.9.0", rows[2].Since)
+	assert.Equal(t, "v1.10.0", rows[2].ChangedIn)
+}
+
+func TestValueRowsHistoryAtRevision(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	valuesFile := filepath.Join(dir, "values.yaml")
+	runGit(t, dir, "init", "-q")
+
+	for _, version := range []string{"v1", "v2"} {
+		require.NoError(t, os.WriteFile(valuesFile, []byte("# -- Version\nversion: "+version+"\n"), 0644))
+		runGit(t, dir, "add", "-A")
+		runGit(t, dir, "commit", "-q", "-m", version)
+		runGit(t, dir, "tag", version)
+	}
+
+	rows := []ValueRow{{Key: "version"}}
+	options := Options{FS: util.NewGitFileSystem(util.OSFileSystem)}
+
+	// Tags after the documented revision aren't part of its history
+	assert.Empty(t, addValueRowsHistory(rows, "git:v1:"+valuesFile, options))
+	assert.Equal(t, "v1", rows[0].Since)
+	assert.Equal(t, "", rows[0].ChangedIn)
+
+	assert.Empty(t, addValueRowsHistory(rows, valuesFile, options))
+	assert.Equal(t, "v1", rows[0].Since)
+	assert.Equal(t, "v2", rows[0].ChangedIn)
+}
+
+func TestValueRowsHistoryWarnings(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	valuesFile := filepath.Join(t.TempDir(), "values.yaml")
+	require.NoError(t, os.WriteFile(valuesFile, []byte("# -- Replicas\nreplicas: 1\n"), 0644))
+	rows := []ValueRow{{Key: "replicas"}}
+
+	// Values files of other filesystems are skipped rather than looked up in the repository of the working directory
+	assert.Empty(t, addValueRowsHistory(rows, "values.yaml", Options{FS: fstest.MapFS{}}))
+
+	warnings := addValueRowsHistory(rows, valuesFile, Options{})
+	require.Len(t, warnings, 1)
+	assert.Equal(t, diagnostic.GitHistoryRule, warnings[0].Rule)
+	assert.Equal(t, valuesFile, warnings[0].File)
+	assert.Equal(t, "", rows[0].Since)
+}
+
+func parseValuesFileForTest(valuesFile string) (*yaml.Node, error) {
+	contents, err := os.ReadFile(valuesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	valuesData, _, err := helm.ParseValuesContents(valuesFile, contents)
+	return valuesData, err
+}
@@ -0,0 +1,86 @@
+package document
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/theEndBeta/yaml-docs/pkg/helm"
+	"github.com/theEndBeta/yaml-docs/pkg/util"
+)
+
+// Characters with special meaning to roff anywhere in a line: backslashes start escapes, and hyphens would be rendered
+// as hyphens rather than minus signs
+var roffEscaper = strings.NewReplacer(`\`, `\e`, "-", `\-`)
+
+// A period or apostrophe at the start of a line would turn the line into a request, and is prefixed with a zero width
+// character
+func escapeRoff(text string) string {
+	lines := strings.Split(roffEscaper.Replace(text), "\n")
+
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// A man page is named for what its values configure: the chart the values file belongs to, or else the directory holding
+// the values file, which is usually named for the tool
+func getManPageName(options Options, chart helm.ChartMetadata) string {
+	if options.ManPageName != "" {
+		return options.ManPageName
+	}
+
+	if chart.Name != "" {
+		return chart.Name
+	}
+
+	if len(options.ValuesFiles) == 0 {
+		return "values"
+	}
+
+	valuesFile := options.ValuesFiles[0]
+	if _, path, isGitPath := util.ParseGitPath(valuesFile); isGitPath {
+		valuesFile = path
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(valuesFile))
+	if err != nil {
+		dir = filepath.Dir(valuesFile)
+	}
+
+	return filepath.Base(dir)
+}
+
+func renderManPage(output io.Writer, rows []ValueRow, manPageName string, yamlDocsVersion string) error {
+	var page strings.Builder
+
+	fmt.Fprintf(&page, ".TH \"%s\" \"5\" \"\" \"yaml-docs %s\" \"File Formats Manual\"\n", escapeRoff(strings.ToUpper(manPageName)), escapeRoff(yamlDocsVersion))
+	page.WriteString(".SH NAME\n")
+	fmt.Fprintf(&page, "%s \\- configuration file reference\n", escapeRoff(manPageName))
+	page.WriteString(".SH DESCRIPTION\n")
+	fmt.Fprintf(&page, "The following keys may be set in\n.IR %s .\n", escapeRoff(manPageName))
+
+	for _, row := range rows {
+		page.WriteString(".TP\n")
+		fmt.Fprintf(&page, "\\fB%s\\fR\n", escapeRoff(row.Key))
+		if row.LongDefault == "" {
+			fmt.Fprintf(&page, "(\\fI%s\\fR, default: \\fB%s\\fR)\n", escapeRoff(row.Type), escapeRoff(plainDefault(row.Default)))
+		} else {
+			fmt.Fprintf(&page, "(\\fI%s\\fR, default:)\n.RS\n.nf\n%s\n.fi\n.RE\n", escapeRoff(row.Type), escapeRoff(row.LongDefault))
+		}
+
+		if row.Description != "" {
+			page.WriteString(".br\n")
+			page.WriteString(escapeRoff(row.Description))
+			page.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(output, page.String())
+	return err
+}
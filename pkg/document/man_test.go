@@ -0,0 +1,62 @@
+package document
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/theEndBeta/yaml-docs/pkg/helm"
+)
+
+func TestRenderManPage(t *testing.T) {
+	yamlValues := parseYamlValues(`
+# -- Path of the log file, e.g. /var/log/my-tool.log
+logFile: /var/log/my-tool.log
+# -- .dotfiles to ignore
+# more here
+ignore: [.git]
+	`)
+
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
+	require.NoError(t, err)
+
+	var output bytes.Buffer
+	err = renderManPage(&output, valuesRows, "my-tool.yaml", "1.0.0")
+	require.NoError(t, err)
+
+	const expected = `.TH "MY\-TOOL.YAML" "5" "" "yaml-docs 1.0.0" "File Formats Manual"
+.SH NAME
+my\-tool.yaml \- configuration file reference
+.SH DESCRIPTION
+The following keys may be set in
+.IR my\-tool.yaml .
+.TP
+\fBignore\fR
+(\fIlist\fR, default: \fB[".git"]\fR)
+.br
+\&.dotfiles to ignore more here
+.TP
+\fBlogFile\fR
+(\fIstring\fR, default: \fB"/var/log/my\-tool.log"\fR)
+.br
+Path of the log file, e.g. /var/log/my\-tool.log
+`
+	assert.Equal(t, expected, output.String())
+}
+
+func TestEscapeRoff(t *testing.T) {
+	assert.Equal(t, `\&.br is not a request`, escapeRoff(".br is not a request"))
+	assert.Equal(t, "first line\n\\&'quoted'\n\\&.git", escapeRoff("first line\n'quoted'\n.git"))
+	assert.Equal(t, `a \e. b \- c.`, escapeRoff(`a \. b - c.`))
+}
+
+func TestGetManPageName(t *testing.T) {
+	chart := helm.ChartMetadata{Name: "my-chart"}
+
+	assert.Equal(t, "override", getManPageName(Options{ManPageName: "override", ValuesFiles: []string{"my-tool/values.yaml"}}, chart))
+	assert.Equal(t, "my-chart", getManPageName(Options{ValuesFiles: []string{"my-tool/values.yaml"}}, chart))
+	assert.Equal(t, "my-tool", getManPageName(Options{ValuesFiles: []string{"my-tool/values.yaml"}}, helm.ChartMetadata{}))
+	assert.Equal(t, "my-tool", getManPageName(Options{ValuesFiles: []string{"git:v1:config/my-tool/values.yaml"}}, helm.ChartMetadata{}))
+}
@@ -6,85 +6,168 @@ import (
 	"strconv"
 
 	log "github.com/sirupsen/logrus"
-	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+	"github.com/theEndBeta/yaml-docs/pkg/helm"
 )
 
-type valueRow struct {
+// ValueRow is a single documented key of the values file, as rendered in the values table
+type ValueRow struct {
 	Key             string
 	Type            string
 	Default         string
+	LongDefault     string
 	Description     string
 	Column          int
 	LineNumber      int
+
+	// First git tag whose values file contained the key, and last tag that changed its default, with GitHistory
+	Since     string
+	ChangedIn string
+
+	// Key of the subchart the value is passed to, with DocumentSubcharts, and the default of the subchart if the
+	// values file overrides it with a different one
+	Subchart        string
+	SubchartDefault string
+
+	// Whether the field must be set and the values it is restricted to, for the fields of custom resources and the
+	// inputs of GitHub actions
+	Required bool
+	Enum     []string
+
+	// Why the input of a GitHub action is deprecated, empty unless it is
+	DeprecationMessage string
 }
 
-type chartTemplateData struct {
-	YamlDocsVersion string
-	Values          []valueRow
+// TemplateData is the data that documentation templates are executed with. Profiles with several tables of values give
+// each table its own Values and LongDefaultStyle fields, so that the values table templates can render those as well.
+type TemplateData struct {
+	YamlDocsVersion  string
+	Values           []ValueRow
+	ValuesTree       []ValueTreeNode
+	LongDefaultStyle string
+
+	// Metadata from the Chart.yaml next to the values file, the zero value when there is none
+	Chart helm.ChartMetadata
+
+	// Custom resource definitions read with CRDProfile, in the order of the manifests
+	CustomResources []CustomResource
+
+	// Metadata of the GitHub action read with ActionProfile
+	Action ActionMetadata
+
+	// Services of the compose files read with ComposeProfile, in the order of the files
+	ComposeServices []ComposeService
 }
 
-func getSortedValuesTableRows(documentRoot *yaml.Node) ([]valueRow, error) {
+func getSortedValuesTableRows(documentRoot *yaml.Node, options Options) ([]ValueRow, error) {
 	valuesTableRows, err := createValueRowsFromField(
 		"",
 		nil,
 		documentRoot,
 		true,
+		options,
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
-	sortOrder := viper.GetString("sort-values-order")
+	sortOrder := options.SortValuesOrder
+	if sortOrder == "" {
+		log.Debugf("No sort order provided, defaulting to %s", AlphaNumSortOrder)
+	} else if sortOrder != AlphaNumSortOrder && sortOrder != FileSortOrder {
+		log.Infof("Invalid sort order `%s`, defaulting to %s", sortOrder, AlphaNumSortOrder)
+	}
+
+	sortValueRows(valuesTableRows, sortOrder)
+	return valuesTableRows, nil
+}
+
+func sortValueRows(valuesTableRows []ValueRow, sortOrder string) {
 	if sortOrder == FileSortOrder {
-		sort.Slice(valuesTableRows, func(i, j int) bool {
+		sort.SliceStable(valuesTableRows, func(i, j int) bool {
 			if valuesTableRows[i].LineNumber == valuesTableRows[j].LineNumber {
 				return valuesTableRows[i].Column < valuesTableRows[j].Column
 			}
 
-			return valuesTableRows[i].LineNumber < valuesTableRows[i].LineNumber
+			return valuesTableRows[i].LineNumber < valuesTableRows[j].LineNumber
 		})
 	} else { // Default to AlphaNumSortOrder
-		if sortOrder == "" {
-			log.Debugf("No sort order provided, defaulting to %s", AlphaNumSortOrder)
-		} else if sortOrder != AlphaNumSortOrder {
-			log.Infof("Invalid sort order `%s`, defaulting to %s", sortOrder, AlphaNumSortOrder)
-		}
-
 		sort.Slice(valuesTableRows, func(i, j int) bool {
 			return valuesTableRows[i].Key < valuesTableRows[j].Key
 		})
 	}
-
-	return valuesTableRows, nil
 }
 
+// Empty values files, including merged values files that are all empty, have no root mapping
+func isEmptyValuesDocument(valuesData *yaml.Node) bool {
+	return valuesData.Kind == 0 || valuesData.Kind == yaml.DocumentNode && len(valuesData.Content) == 0
+}
 
-func getChartTemplateData(valuesData *yaml.Node, yamlDocsVersion string) (chartTemplateData, error) {
+func getTemplateData(valuesData *yaml.Node, options Options) (TemplateData, error) {
 	// handle empty values file case
-	if valuesData.Kind == 0 {
-		return chartTemplateData{
-			YamlDocsVersion:        yamlDocsVersion,
-			Values:                 make([]valueRow, 0),
+	if isEmptyValuesDocument(valuesData) {
+		return TemplateData{
+			YamlDocsVersion:        options.YamlDocsVersion,
+			Values:                 make([]ValueRow, 0),
+			ValuesTree:             make([]ValueTreeNode, 0),
+			LongDefaultStyle:       options.LongDefaultStyle,
 		}, nil
 	}
 
 	if valuesData.Kind != yaml.DocumentNode {
-		return chartTemplateData{}, fmt.Errorf("invalid node kind supplied: %d", valuesData.Kind)
+		return TemplateData{}, fmt.Errorf("invalid node kind supplied: %d", valuesData.Kind)
 	}
 	if valuesData.Content[0].Kind != yaml.MappingNode {
-		return chartTemplateData{}, fmt.Errorf("values file must resolve to a map, not %s", strconv.Itoa(int(valuesData.Kind)))
+		return TemplateData{}, fmt.Errorf("values file must resolve to a map, not %s", strconv.Itoa(int(valuesData.Kind)))
 	}
 
-	valuesTableRows, err := getSortedValuesTableRows(valuesData.Content[0])
+	valuesTableRows, err := getSortedValuesTableRows(valuesData.Content[0], options)
 
 	if err != nil {
-		return chartTemplateData{}, err
+		return TemplateData{}, err
 	}
 
-	return chartTemplateData{
-		YamlDocsVersion:        yamlDocsVersion,
+	valuesTree := getValuesTree(valuesData.Content[0], valuesTableRows, options)
+
+	return TemplateData{
+		YamlDocsVersion:        options.YamlDocsVersion,
 		Values:                 valuesTableRows,
+		ValuesTree:             valuesTree,
+		LongDefaultStyle:       options.LongDefaultStyle,
 	}, nil
 }
+
+// ValueRows returns the rows the values table is rendered from, sorted as configured by options
+func ValueRows(valuesData *yaml.Node, options Options) ([]ValueRow, error) {
+	templateData, err := getTemplateData(valuesData, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return templateData.Values, nil
+}
+
+// UndocumentedKeys reports the rows of a values file that are documented without a description
+func UndocumentedKeys(rows []ValueRow, file string) []diagnostic.Diagnostic {
+	notes := make([]diagnostic.Diagnostic, 0)
+
+	for _, row := range rows {
+		if row.Description != "" {
+			continue
+		}
+
+		notes = append(notes, diagnostic.Diagnostic{
+			File:     file,
+			Line:     row.LineNumber,
+			Column:   row.Column,
+			Rule:     diagnostic.UndocumentedKeyRule,
+			Severity: diagnostic.SeverityNote,
+			Message:  fmt.Sprintf("%s has no description, add a `# --` comment above it", row.Key),
+		})
+	}
+
+	return notes
+}
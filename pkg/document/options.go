@@ -0,0 +1,95 @@
+package document
+
+import (
+	"io/fs"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+	"github.com/theEndBeta/yaml-docs/pkg/util"
+)
+
+// Options controls how documentation is generated. The zero value renders the default markdown template, with values
+// sorted alphanumerically and defaults rendered as JSON.
+type Options struct {
+	// Filesystem that values and template files are read from, the host filesystem when nil
+	FS fs.FS
+
+	// Values files to document, parsed and documented in order
+	ValuesFiles []string
+
+	// Kind of yaml files to document, ValuesProfile, CRDProfile for the CustomResourceDefinition manifests given as
+	// ValuesFiles, ActionProfile for the action.yml of a GitHub action or ComposeProfile for compose files
+	Profile string
+
+	// Gotemplate files rendered into markdown documentation. The default template is used if any of them is missing
+	TemplateFiles []string
+
+	// Order of the values rows, AlphaNumSortOrder or FileSortOrder
+	SortValuesOrder string
+
+	// Format of the generated documentation, MarkdownOutputFormat or one of the other output formats
+	OutputFormat string
+
+	// Columns written in CSVOutputFormat and TSVOutputFormat
+	Columns []string
+
+	// Path the documentation is written to by PrintDocumentation, DefaultOutputFile when empty
+	OutputFile string
+
+	// Name of the documented file in ManOutputFormat, the name of the chart or of the directory of the first values file
+	// when empty
+	ManPageName string
+
+	// Markup that keys, defaults and descriptions are escaped for by the templates, GitHubEscapeFormat by default
+	EscapeFormat string
+
+	// Format of defaults, JSONDefaultFormat, YAMLDefaultFormat or FlowYAMLDefaultFormat
+	DefaultFormat string
+
+	// Length above which defaults are moved out of the values table, 0 to only move multi-line defaults
+	DefaultMaxLength int
+
+	// How defaults moved out of the values table are rendered, DetailsLongDefaultStyle or FootnoteLongDefaultStyle
+	LongDefaultStyle string
+
+	// Version of yaml-docs shown in the footer of the default template, the footer is omitted when empty
+	YamlDocsVersion string
+
+	// Fail generation with a StrictError if there are any warnings, rather than only reporting them
+	Strict bool
+
+	// Derive the Since and ChangedIn fields of each row from the git tags of the repository containing the values file
+	GitHistory bool
+
+	// Document the values of the dependencies of the chart next to the first values file, as the chart sets them
+	DocumentSubcharts bool
+
+	// Called with every warning found while generating, warnings are logged when nil
+	OnWarning func(diagnostic.Diagnostic)
+}
+
+// FileSystem returns the file system values, templates and chart files are read from
+func (o Options) FileSystem() fs.FS {
+	if o.FS == nil {
+		return util.OSFileSystem
+	}
+
+	return o.FS
+}
+
+func (o Options) reportWarnings(warnings []diagnostic.Diagnostic) error {
+	for _, warning := range warnings {
+		if o.OnWarning != nil {
+			o.OnWarning(warning)
+		} else {
+			log.Warn(warning.String())
+		}
+	}
+
+	if o.Strict && len(warnings) > 0 {
+		return &StrictError{Warnings: warnings}
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package document
+
+import (
+	"gopkg.in/yaml.v3"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+	"github.com/theEndBeta/yaml-docs/pkg/helm"
+)
+
+// globalValuesKey holds the values helm passes on to every subchart under the same key, rather than under the key of
+// the subchart
+const globalValuesKey = "global"
+
+// Rows of a subchart are keyed as the parent chart sets them, under the key of the subchart except for global values
+func getSubchartValueRows(subchart helm.Subchart, options Options) ([]ValueRow, error) {
+	valuesTableRows := make([]ValueRow, 0)
+	if len(subchart.Values.Content) == 0 || subchart.Values.Content[0].Kind != yaml.MappingNode {
+		return valuesTableRows, nil
+	}
+
+	values := subchart.Values.Content[0]
+	subchartPrefix := formatNextObjectKeyPrefix("", subchart.Key())
+
+	for i := 0; i < len(values.Content); i += 2 {
+		k := values.Content[i]
+		v := values.Content[i+1]
+
+		prefix := subchartPrefix
+		if k.Value == globalValuesKey {
+			prefix = ""
+		}
+
+		rows, err := createValueRowsFromField(formatNextObjectKeyPrefix(prefix, k.Value), k, v, true, options)
+		if err != nil {
+			return nil, err
+		}
+
+		valuesTableRows = append(valuesTableRows, rows...)
+	}
+
+	sortValueRows(valuesTableRows, options.SortValuesOrder)
+
+	for i := range valuesTableRows {
+		valuesTableRows[i].Subchart = subchart.Key()
+	}
+
+	return valuesTableRows, nil
+}
+
+// Values of subcharts that the parent chart documents as well are shown once, with the default of the parent and the
+// default of the subchart noted if the parent overrides it. Values under an object or list the parent documents as a whole are left out, the
+// parent documents their defaults. The first subchart defining a global value documents it.
+func addSubchartValueRows(valuesTableRows []ValueRow, valuesData *yaml.Node, chart helm.ChartMetadata, options Options) ([]ValueRow, []diagnostic.Diagnostic, error) {
+	if len(options.ValuesFiles) == 0 || len(chart.Dependencies) == 0 {
+		return valuesTableRows, nil, nil
+	}
+
+	subcharts, warnings := helm.ParseSubcharts(options.FileSystem(), helm.ChartFilePath(options.ValuesFiles[0]), chart)
+
+	parentKeys := KeyPaths(valuesData)
+	rowIndices := make(map[string]int, len(valuesTableRows))
+	for i, row := range valuesTableRows {
+		rowIndices[row.Key] = i
+	}
+
+	for _, subchart := range subcharts {
+		subchartRows, err := getSubchartValueRows(subchart, options)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, subchartRow := range subchartRows {
+			i, documented := rowIndices[subchartRow.Key]
+
+			if documented && valuesTableRows[i].Subchart == "" {
+				valuesTableRows[i].Subchart = subchartRow.Subchart
+				if fullDefault(valuesTableRows[i]) != fullDefault(subchartRow) {
+					valuesTableRows[i].SubchartDefault = subchartRow.Default
+				}
+
+				if valuesTableRows[i].Description == "" {
+					valuesTableRows[i].Description = subchartRow.Description
+				}
+			}
+
+			if documented || parentKeys[subchartRow.Key] {
+				continue
+			}
+
+			rowIndices[subchartRow.Key] = len(valuesTableRows)
+			valuesTableRows = append(valuesTableRows, subchartRow)
+		}
+	}
+
+	// Subchart rows come from other files, whose line numbers don't compare with those of the parent. In file order they
+	// stay after the rows of the parent, in the order of the dependencies.
+	if options.SortValuesOrder != FileSortOrder {
+		sortValueRows(valuesTableRows, options.SortValuesOrder)
+	}
+
+	return valuesTableRows, warnings, nil
+}
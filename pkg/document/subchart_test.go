@@ -0,0 +1,105 @@
+package document
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSubchartValues(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Chart.yaml": {Data: []byte(`apiVersion: v2
+name: umbrella
+dependencies:
+  - name: postgresql
+  - name: redis
+    alias: cache
+`)},
+		"values.yaml": {Data: []byte(`global:
+  # -- Registry of all images
+  imageRegistry: registry.example.com
+postgresql:
+  auth:
+    username: app
+cache:
+  # -- Persistence of the cache
+  persistence:
+    enabled: false
+  # -- Number of cache replicas
+  replicas: 3
+`)},
+		"charts/postgresql/values.yaml": {Data: []byte(`global:
+  # -- Global image registry
+  imageRegistry: ""
+  # -- Global storage class
+  storageClass: ""
+auth:
+  # -- Name of the user to create
+  username: ""
+  # -- Password of the user
+  password: ""
+`)},
+		"charts/redis/values.yaml": {Data: []byte(`global:
+  # -- Storage class of redis
+  storageClass: redis
+persistence:
+  # -- Persist data
+  enabled: true
+# -- Number of replicas
+replicas: 3
+`)},
+	}
+
+	options := Options{FS: fsys, ValuesFiles: []string{"values.yaml"}, TemplateFiles: []string{"README.md.gotmpl"}}
+	fsys["README.md.gotmpl"] = &fstest.MapFile{Data: []byte(`{{ range .Values }}{{ .Key }}={{ .Subchart }}
+{{ end }}`)}
+
+	output, err := Generate(options)
+	require.NoError(t, err)
+	assert.Equal(t, "cache.persistence=\ncache.replicas=\nglobal.imageRegistry=\npostgresql.auth.username=\n", string(output))
+
+	options.DocumentSubcharts = true
+	output, err = Generate(options)
+	require.NoError(t, err)
+	assert.Equal(t, "cache.persistence=\n"+
+		"cache.replicas=cache\n"+
+		"global.imageRegistry=postgresql\n"+
+		"global.storageClass=postgresql\n"+
+		"postgresql.auth.password=postgresql\n"+
+		"postgresql.auth.username=postgresql\n", string(output))
+
+	fsys["README.md.gotmpl"] = &fstest.MapFile{Data: []byte(`{{ template "docs.valuesTable" . }}`)}
+	output, err = Generate(options)
+
+	const expected = "| Key | Type | Default | Description |\n" +
+		"|-----|------|---------|-------------|\n" +
+		"| cache.persistence | object | `{\"enabled\":false}` | Persistence of the cache |\n" +
+		"| cache.replicas | int | `3` | Number of cache replicas |\n" +
+		"| global.imageRegistry | string | `\"registry.example.com\"` | Registry of all images (overrides the postgresql default `\"\"`) |\n" +
+		"| global.storageClass | string | `\"\"` | Global storage class |\n" +
+		"| postgresql.auth.password | string | `\"\"` | Password of the user |\n" +
+		"| postgresql.auth.username | string | `\"app\"` | Name of the user to create (overrides the postgresql default `\"\"`) |"
+
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(output))
+}
+
+func TestGenerateSubchartValuesEmptyValuesFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Chart.yaml":               {Data: []byte("apiVersion: v2\nname: umbrella\ndependencies:\n  - name: redis\n")},
+		"values.yaml":              {Data: []byte("")},
+		"charts/redis/values.yaml": {Data: []byte("# -- Number of replicas\nreplicas: 3\n")},
+		"README.md.gotmpl":         {Data: []byte(`{{ range .Values }}{{ .Key }}={{ .Subchart }}{{ end }} {{ len .ValuesTree }}`)},
+		"without-deps/Chart.yaml":  {Data: []byte("apiVersion: v2\nname: empty\n")},
+		"without-deps/values.yaml": {Data: []byte("")},
+	}
+
+	output, err := Generate(Options{FS: fsys, ValuesFiles: []string{"values.yaml"}, TemplateFiles: []string{"README.md.gotmpl"}, DocumentSubcharts: true})
+	require.NoError(t, err)
+	assert.Equal(t, "redis.replicas=redis 0", string(output))
+
+	_, err = Generate(Options{FS: fsys, ValuesFiles: []string{"without-deps/values.yaml"}, DocumentSubcharts: true})
+	require.NoError(t, err)
+}
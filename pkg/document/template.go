@@ -1,15 +1,16 @@
 package document
 
 import (
-	"io/ioutil"
-	"os"
-	"path"
+	"errors"
+	"fmt"
+	"io/fs"
 	"strings"
 	"text/template"
 
 	"github.com/Masterminds/sprig"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
 )
 
 const defaultDocumentationTemplate = `
@@ -18,17 +19,116 @@ const defaultDocumentationTemplate = `
 {{ template "yaml-docs.versionFooter" . }}
 `
 
+// Summary of the chart for templates to opt in to, the default template leaves it out so that documentation of values
+// files which happen to sit next to a Chart.yaml doesn't change
+const chartSummaryTemplate = `{{ if .Chart.Name }}{{ template "chart.header" . }}
+{{ template "chart.deprecationWarning" . }}
+
+{{ template "chart.badgesSection" . }}
+
+{{ template "chart.description" . }}
+
+{{ template "chart.homepageLine" . }}
+
+{{ template "chart.maintainersSection" . }}
+
+{{ template "chart.sourcesSection" . }}
+
+{{ if .Chart.KubeVersion }}{{ template "chart.requirementsHeader" . }}
+
+{{ template "chart.kubeVersionLine" . }}{{ end }}
+
+{{ template "chart.dependenciesSection" . }}
+{{ end }}`
+
+const defaultCustomResourcesTemplate = `{{ template "crd.resourcesSection" . }}
+
+{{ template "yaml-docs.versionFooter" . }}
+`
+
+const defaultActionTemplate = `{{ template "action.header" . }}
+
+{{ template "action.description" . }}
+
+{{ template "action.inputsSection" . }}
+
+{{ template "action.outputsSection" . }}
+
+{{ template "action.usageSection" . }}
+
+{{ template "yaml-docs.versionFooter" . }}
+`
+
+const defaultComposeTemplate = `{{ template "compose.servicesSection" . }}
+
+{{ template "yaml-docs.versionFooter" . }}
+`
+
+func getDefaultDocumentationTemplate(profile string) string {
+	switch profile {
+	case CRDProfile:
+		return defaultCustomResourcesTemplate
+	case ActionProfile:
+		return defaultActionTemplate
+	case ComposeProfile:
+		return defaultComposeTemplate
+	}
+
+	return defaultDocumentationTemplate
+}
+
 // String providing three templates: value
 func getValuesTableTemplates() string {
 	valuesSectionBuilder := strings.Builder{}
 	valuesSectionBuilder.WriteString(`{{ define "docs.valuesHeader" }}## Values{{ end }}`)
 
-	valuesSectionBuilder.WriteString(`{{ define "docs.valuesTable" }}`)
-	valuesSectionBuilder.WriteString("| Key | Type | Default | Description |\n")
-	valuesSectionBuilder.WriteString("|-----|------|---------|-------------|\n")
-	valuesSectionBuilder.WriteString("  {{- range .Values }}")
-	valuesSectionBuilder.WriteString("\n| {{ .Key }} | {{ .Type }} | {{ .Default }} | {{ .Description }} |")
+	// Defaults that are too long for the table are either folded into a details element in the table cell, or moved
+	// into a footnote below the table
+	valuesSectionBuilder.WriteString(`{{ define "docs.valueDefault" }}`)
+	valuesSectionBuilder.WriteString("{{- if not .Row.LongDefault }}{{ escapeDefault .Row.Default }}")
+	valuesSectionBuilder.WriteString(fmt.Sprintf("{{- else if eq .Style %q }}{{ escapeDefault .Row.Default }}[^default-{{ .Index }}]", FootnoteLongDefaultStyle))
+	valuesSectionBuilder.WriteString(`{{- else }}<details><summary>{{ escapeDefault .Row.Default }}</summary><pre lang="yaml">{{ escapeCodeBlock .Row.LongDefault }}</pre></details>`)
+	valuesSectionBuilder.WriteString("{{- end }}")
+	valuesSectionBuilder.WriteString("{{ end }}")
+
+	valuesSectionBuilder.WriteString(`{{ define "docs.valuesDefaultFootnotes" }}`)
+	valuesSectionBuilder.WriteString(fmt.Sprintf("{{- if eq .LongDefaultStyle %q }}", FootnoteLongDefaultStyle))
+	valuesSectionBuilder.WriteString("{{- range $index, $row := .Values }}{{ if .LongDefault }}")
+	valuesSectionBuilder.WriteString("\n\n[^default-{{ $index }}]: Default value of {{ escapeKey .Key }}:\n\n")
+	valuesSectionBuilder.WriteString("    ```yaml\n{{ indent 4 .LongDefault }}\n    ```")
+	valuesSectionBuilder.WriteString("{{- end }}{{ end }}")
+	valuesSectionBuilder.WriteString("{{- end }}")
+	valuesSectionBuilder.WriteString("{{ end }}")
+
+	// A cell of a table of value rows, the tables of all profiles are made of these
+	valuesSectionBuilder.WriteString(`{{ define "docs.valueCell" }}`)
+	valuesSectionBuilder.WriteString(`{{- if eq .Column "key" }}{{ escapeKey .Row.Key }}`)
+	valuesSectionBuilder.WriteString(`{{- else if eq .Column "type" }}{{ .Row.Type }}`)
+	valuesSectionBuilder.WriteString(`{{- else if eq .Column "required" }}{{ if .Row.Required }}yes{{ else }}no{{ end }}`)
+	valuesSectionBuilder.WriteString(`{{- else if eq .Column "default" }}{{ template "docs.valueDefault" . }}`)
+	valuesSectionBuilder.WriteString(`{{- else if eq .Column "enum" }}{{ range $i, $value := .Row.Enum }}{{ if $i }}, {{ end }}{{ escapeDefault $value }}{{ end }}`)
+	valuesSectionBuilder.WriteString(`{{- else if eq .Column "description" }}`)
+	valuesSectionBuilder.WriteString("{{ if .Row.DeprecationMessage }}**Deprecated:** {{ escapeDescription .Row.DeprecationMessage }}{{ if .Row.Description }} {{ end }}{{ end }}")
+	valuesSectionBuilder.WriteString("{{ escapeDescription .Row.Description }}")
+	valuesSectionBuilder.WriteString("{{ if .Row.SubchartDefault }}{{ if .Row.Description }} {{ end }}(overrides the {{ escapeKey .Row.Subchart }} default {{ escapeDefault .Row.SubchartDefault }}){{ end }}")
+	valuesSectionBuilder.WriteString("{{- end }}")
+	valuesSectionBuilder.WriteString("{{ end }}")
+
+	// Table of the Values with the given Headers and Columns, each column one of the cases of docs.valueCell
+	valuesSectionBuilder.WriteString(`{{ define "docs.table" }}`)
+	valuesSectionBuilder.WriteString("|{{ range .Headers }} {{ . }} |{{ end }}\n")
+	valuesSectionBuilder.WriteString("|{{ range .Headers }}{{ repeat (int (add 2 (len .))) \"-\" }}|{{ end }}\n")
+	valuesSectionBuilder.WriteString("  {{- range $index, $row := .Values }}")
+	valuesSectionBuilder.WriteString("\n|{{ range $.Columns }} ")
+	valuesSectionBuilder.WriteString(`{{ template "docs.valueCell" (dict "Column" . "Row" $row "Index" $index "Style" $.LongDefaultStyle) }}`)
+	valuesSectionBuilder.WriteString(" |{{ end }}")
 	valuesSectionBuilder.WriteString("  {{- end }}")
+	valuesSectionBuilder.WriteString(`{{ template "docs.valuesDefaultFootnotes" . }}`)
+	valuesSectionBuilder.WriteString("{{ end }}")
+
+	valuesSectionBuilder.WriteString(`{{ define "docs.valuesTable" }}`)
+	valuesSectionBuilder.WriteString(`{{ template "docs.table" (dict "Headers" (list "Key" "Type" "Default" "Description")`)
+	valuesSectionBuilder.WriteString(` "Columns" (list "key" "type" "default" "description") "Values" .Values "LongDefaultStyle" .LongDefaultStyle) }}`)
 	valuesSectionBuilder.WriteString("{{ end }}")
 
 	valuesSectionBuilder.WriteString(`{{ define "docs.valuesSection" }}`)
@@ -42,6 +142,315 @@ func getValuesTableTemplates() string {
 	return valuesSectionBuilder.String()
 }
 
+// Templates rendering the values as a nested list following the structure of the values file, rather than as a table
+func getValuesTreeTemplates() string {
+	valuesTreeBuilder := strings.Builder{}
+
+	valuesTreeBuilder.WriteString(`{{ define "docs.valuesTreeNode" }}`)
+	valuesTreeBuilder.WriteString("\n{{ repeat .Depth \"  \" }}- **{{ escapeKey .Name }}** (`{{ .Type }}`")
+	valuesTreeBuilder.WriteString("{{ if and .Documented (not .Children) }}, default: ")
+	valuesTreeBuilder.WriteString(`{{ template "docs.valueDefault" (dict "Row" . "Index" .Index "Style" .LongDefaultStyle) }}{{ end }})`)
+	valuesTreeBuilder.WriteString("{{ if .Description }} {{ escapeDescription .Description }}{{ end }}")
+	valuesTreeBuilder.WriteString("  {{- range .Children }}{{ template \"docs.valuesTreeNode\" . }}{{ end }}")
+	valuesTreeBuilder.WriteString("{{ end }}")
+
+	valuesTreeBuilder.WriteString(`{{ define "docs.valuesTree" }}`)
+	valuesTreeBuilder.WriteString("  {{- range .ValuesTree }}{{ template \"docs.valuesTreeNode\" . }}{{ end }}")
+	valuesTreeBuilder.WriteString(`{{ template "docs.valuesDefaultFootnotes" . }}`)
+	valuesTreeBuilder.WriteString("{{ end }}")
+
+	valuesTreeBuilder.WriteString(`{{ define "docs.valuesTreeSection" }}`)
+	valuesTreeBuilder.WriteString("{{ if .ValuesTree }}")
+	valuesTreeBuilder.WriteString(`{{ template "docs.valuesHeader" . }}`)
+	valuesTreeBuilder.WriteString("\n")
+	valuesTreeBuilder.WriteString(`{{ template "docs.valuesTree" . }}`)
+	valuesTreeBuilder.WriteString("{{ end }}")
+	valuesTreeBuilder.WriteString("{{ end }}")
+
+	return valuesTreeBuilder.String()
+}
+
+// Templates rendering the metadata from Chart.yaml, which render nothing for fields that aren't set. The names match
+// those of helm-docs, so that its templates can be reused.
+func getChartTemplates() string {
+	chartBuilder := strings.Builder{}
+	chartBuilder.WriteString(`{{ define "chart.name" }}{{ .Chart.Name }}{{ end }}`)
+	chartBuilder.WriteString(`{{ define "chart.header" }}{{ if .Chart.Name }}# {{ .Chart.Name }}`)
+	chartBuilder.WriteString("\n{{ end }}{{ end }}")
+	chartBuilder.WriteString(`{{ define "chart.description" }}{{ .Chart.Description }}{{ end }}`)
+	chartBuilder.WriteString(`{{ define "chart.deprecationWarning" }}{{ if .Chart.Deprecated }}> **:exclamation: This Helm Chart is deprecated!**{{ end }}{{ end }}`)
+	chartBuilder.WriteString(`{{ define "chart.version" }}{{ .Chart.Version }}{{ end }}`)
+	chartBuilder.WriteString(`{{ define "chart.type" }}{{ .Chart.Type }}{{ end }}`)
+	chartBuilder.WriteString(`{{ define "chart.appVersion" }}{{ .Chart.AppVersion }}{{ end }}`)
+	chartBuilder.WriteString(`{{ define "chart.homepage" }}{{ .Chart.Home }}{{ end }}`)
+	chartBuilder.WriteString(`{{ define "chart.homepageLine" }}{{ if .Chart.Home }}**Homepage:** <{{ .Chart.Home }}>{{ end }}{{ end }}`)
+	chartBuilder.WriteString(`{{ define "chart.kubeVersion" }}{{ .Chart.KubeVersion }}{{ end }}`)
+	chartBuilder.WriteString("{{ define \"chart.kubeVersionLine\" }}{{ if .Chart.KubeVersion }}Kubernetes: `{{ .Chart.KubeVersion }}`{{ end }}{{ end }}")
+	chartBuilder.WriteString(`{{ define "chart.keywords" }}{{ join ", " .Chart.Keywords }}{{ end }}`)
+	chartBuilder.WriteString(`{{ define "chart.keywordsLine" }}{{ if .Chart.Keywords }}**Keywords:** {{ template "chart.keywords" . }}{{ end }}{{ end }}`)
+
+	// Badges end in a space, so that they can be placed right after each other
+	chartBuilder.WriteString(`{{ define "chart.badge" }}`)
+	chartBuilder.WriteString(`![{{ .Label }}: {{ .Value }}](https://img.shields.io/badge/{{ .Label }}-{{ .Value | replace "-" "--" | replace "_" "__" }}-informational?style=flat-square) `)
+	chartBuilder.WriteString("{{ end }}")
+	chartBuilder.WriteString(`{{ define "chart.versionBadge" }}{{ if .Chart.Version }}{{ template "chart.badge" (dict "Label" "Version" "Value" .Chart.Version) }}{{ end }}{{ end }}`)
+	chartBuilder.WriteString(`{{ define "chart.typeBadge" }}{{ if .Chart.Type }}{{ template "chart.badge" (dict "Label" "Type" "Value" .Chart.Type) }}{{ end }}{{ end }}`)
+	chartBuilder.WriteString(`{{ define "chart.appVersionBadge" }}{{ if .Chart.AppVersion }}{{ template "chart.badge" (dict "Label" "AppVersion" "Value" .Chart.AppVersion) }}{{ end }}{{ end }}`)
+	chartBuilder.WriteString(`{{ define "chart.badgesSection" }}`)
+	chartBuilder.WriteString(`{{ template "chart.versionBadge" . }}{{ template "chart.typeBadge" . }}{{ template "chart.appVersionBadge" . }}`)
+	chartBuilder.WriteString("{{ end }}")
+
+	chartBuilder.WriteString(`{{ define "chart.maintainersHeader" }}## Maintainers{{ end }}`)
+	chartBuilder.WriteString(`{{ define "chart.maintainersTable" }}`)
+	chartBuilder.WriteString("| Name | Email | Url |\n")
+	chartBuilder.WriteString("| ---- | ------ | --- |")
+	chartBuilder.WriteString("  {{- range .Chart.Maintainers }}")
+	chartBuilder.WriteString("\n| {{ escapeDescription .Name }} | {{ escapeDescription .Email }} | {{ escapeDescription .URL }} |")
+	chartBuilder.WriteString("  {{- end }}")
+	chartBuilder.WriteString("{{ end }}")
+	chartBuilder.WriteString(`{{ define "chart.maintainersSection" }}`)
+	chartBuilder.WriteString("{{ if .Chart.Maintainers }}")
+	chartBuilder.WriteString(`{{ template "chart.maintainersHeader" . }}`)
+	chartBuilder.WriteString("\n\n")
+	chartBuilder.WriteString(`{{ template "chart.maintainersTable" . }}`)
+	chartBuilder.WriteString("{{ end }}")
+	chartBuilder.WriteString("{{ end }}")
+
+	chartBuilder.WriteString(`{{ define "chart.sourcesHeader" }}## Source Code{{ end }}`)
+	chartBuilder.WriteString(`{{ define "chart.sourcesList" }}`)
+	chartBuilder.WriteString("{{ range $index, $source := .Chart.Sources }}{{ if $index }}\n{{ end }}* <{{ $source }}>{{ end }}")
+	chartBuilder.WriteString("{{ end }}")
+	chartBuilder.WriteString(`{{ define "chart.sourcesSection" }}`)
+	chartBuilder.WriteString("{{ if .Chart.Sources }}")
+	chartBuilder.WriteString(`{{ template "chart.sourcesHeader" . }}`)
+	chartBuilder.WriteString("\n\n")
+	chartBuilder.WriteString(`{{ template "chart.sourcesList" . }}`)
+	chartBuilder.WriteString("{{ end }}")
+	chartBuilder.WriteString("{{ end }}")
+
+	// The requirements templates render the dependencies the way helm-docs does, the dependencies templates render all
+	// their details
+	chartBuilder.WriteString(`{{ define "chart.requirementsHeader" }}## Requirements{{ end }}`)
+	chartBuilder.WriteString(`{{ define "chart.requirementsTable" }}`)
+	chartBuilder.WriteString("| Repository | Name | Version |\n")
+	chartBuilder.WriteString("|------------|------|---------|")
+	chartBuilder.WriteString("  {{- range .Chart.Dependencies }}")
+	chartBuilder.WriteString("\n| {{ escapeKey .Repository }} | {{ escapeKey .Name }} | {{ escapeKey .Version }} |")
+	chartBuilder.WriteString("  {{- end }}")
+	chartBuilder.WriteString("{{ end }}")
+	chartBuilder.WriteString(`{{ define "chart.requirementsSection" }}`)
+	chartBuilder.WriteString("{{ if or .Chart.KubeVersion .Chart.Dependencies }}")
+	chartBuilder.WriteString(`{{ template "chart.requirementsHeader" . }}`)
+	chartBuilder.WriteString("{{ if .Chart.KubeVersion }}\n\n")
+	chartBuilder.WriteString(`{{ template "chart.kubeVersionLine" . }}`)
+	chartBuilder.WriteString("{{ end }}")
+	chartBuilder.WriteString("{{ if .Chart.Dependencies }}\n\n")
+	chartBuilder.WriteString(`{{ template "chart.requirementsTable" . }}`)
+	chartBuilder.WriteString("{{ end }}")
+	chartBuilder.WriteString("{{ end }}")
+	chartBuilder.WriteString("{{ end }}")
+
+	chartBuilder.WriteString(`{{ define "chart.dependenciesHeader" }}## Dependencies{{ end }}`)
+	chartBuilder.WriteString(`{{ define "chart.dependenciesTable" }}`)
+	chartBuilder.WriteString("| Name | Repository | Version | Resolved Version | Alias | Condition |\n")
+	chartBuilder.WriteString("|------|------------|---------|------------------|-------|-----------|")
+	chartBuilder.WriteString("  {{- range .Chart.Dependencies }}")
+	chartBuilder.WriteString("\n| {{ escapeKey .Name }} | {{ escapeKey .Repository }} | {{ escapeKey .Version }} | {{ escapeKey .ResolvedVersion }}")
+	chartBuilder.WriteString(" | {{ escapeKey .Alias }} | {{ escapeKey .Condition }} |")
+	chartBuilder.WriteString("  {{- end }}")
+	chartBuilder.WriteString("{{ end }}")
+	chartBuilder.WriteString(`{{ define "chart.dependenciesSection" }}`)
+	chartBuilder.WriteString("{{ if .Chart.Dependencies }}")
+	chartBuilder.WriteString(`{{ template "chart.dependenciesHeader" . }}`)
+	chartBuilder.WriteString("\n\n")
+	chartBuilder.WriteString(`{{ template "chart.dependenciesTable" . }}`)
+	chartBuilder.WriteString("{{ end }}")
+	chartBuilder.WriteString("{{ end }}")
+
+	chartBuilder.WriteString(`{{ define "chart.summarySection" }}` + chartSummaryTemplate + "{{ end }}")
+
+	// The values templates under the names helm-docs uses for them
+	chartBuilder.WriteString(`{{ define "chart.valuesHeader" }}{{ template "docs.valuesHeader" . }}{{ end }}`)
+	chartBuilder.WriteString(`{{ define "chart.valuesTable" }}{{ template "docs.valuesTable" . }}{{ end }}`)
+	chartBuilder.WriteString(`{{ define "chart.valuesSection" }}{{ template "docs.valuesSection" . }}{{ end }}`)
+
+	return chartBuilder.String()
+}
+
+// Templates rendering custom resource definitions as a reference of their fields, one table for each version. The field
+// tables render defaults like the values table.
+func getCustomResourceTemplates() string {
+	crdBuilder := strings.Builder{}
+	crdBuilder.WriteString(`{{ define "crd.header" }}## {{ .Kind }}{{ end }}`)
+
+	crdBuilder.WriteString(`{{ define "crd.summary" }}`)
+	crdBuilder.WriteString("{{ if .Group }}- **Group:** `{{ .Group }}`\n{{ end }}")
+	crdBuilder.WriteString("{{ if .Plural }}- **Plural:** `{{ .Plural }}`\n{{ end }}")
+	crdBuilder.WriteString("{{ if .Scope }}- **Scope:** {{ .Scope }}\n{{ end }}")
+	crdBuilder.WriteString("{{ end }}")
+
+	crdBuilder.WriteString(`{{ define "crd.versionHeader" }}### {{ .Name }}{{ if .Storage }} (storage version){{ end }}{{ if not .Served }} (not served){{ end }}{{ end }}`)
+	crdBuilder.WriteString(`{{ define "crd.deprecationWarning" }}`)
+	crdBuilder.WriteString(`{{ if .Deprecated }}> **Deprecated:** {{ default "this version is deprecated" .DeprecationWarning | escapeDescription }}{{ end }}`)
+	crdBuilder.WriteString("{{ end }}")
+
+	crdBuilder.WriteString(`{{ define "crd.fieldsTable" }}`)
+	crdBuilder.WriteString(`{{ template "docs.table" (dict "Headers" (list "Field" "Type" "Required" "Default" "Allowed Values" "Description")`)
+	crdBuilder.WriteString(` "Columns" (list "key" "type" "required" "default" "enum" "description") "Values" .Values "LongDefaultStyle" .LongDefaultStyle) }}`)
+	crdBuilder.WriteString("{{ end }}")
+
+	crdBuilder.WriteString(`{{ define "crd.versionSection" }}`)
+	crdBuilder.WriteString(`{{ template "crd.versionHeader" . }}`)
+	crdBuilder.WriteString("\n\n")
+	crdBuilder.WriteString(`{{ if .Deprecated }}{{ template "crd.deprecationWarning" . }}`)
+	crdBuilder.WriteString("\n\n{{ end }}")
+	crdBuilder.WriteString(`{{ if .Values }}{{ template "crd.fieldsTable" . }}{{ else }}The schema of this version has no fields.{{ end }}`)
+	crdBuilder.WriteString("{{ end }}")
+
+	crdBuilder.WriteString(`{{ define "crd.section" }}`)
+	crdBuilder.WriteString(`{{ template "crd.header" . }}`)
+	crdBuilder.WriteString("\n\n")
+	crdBuilder.WriteString(`{{ template "crd.summary" . }}`)
+	crdBuilder.WriteString(`{{ range .Versions }}`)
+	crdBuilder.WriteString("\n")
+	crdBuilder.WriteString(`{{ template "crd.versionSection" . }}`)
+	crdBuilder.WriteString("\n{{ end }}")
+	crdBuilder.WriteString("{{ end }}")
+
+	crdBuilder.WriteString(`{{ define "crd.resourcesSection" }}`)
+	crdBuilder.WriteString(`{{ range $index, $resource := .CustomResources }}{{ if $index }}`)
+	crdBuilder.WriteString("\n{{ end }}")
+	crdBuilder.WriteString(`{{ template "crd.section" . }}{{ end }}`)
+	crdBuilder.WriteString("{{ end }}")
+
+	return crdBuilder.String()
+}
+
+// Templates rendering the metadata, inputs and outputs of a GitHub action, and a snippet of a workflow step using it
+func getActionTemplates() string {
+	actionBuilder := strings.Builder{}
+	actionBuilder.WriteString(`{{ define "action.header" }}{{ if .Action.Name }}# {{ .Action.Name }}{{ end }}{{ end }}`)
+	actionBuilder.WriteString(`{{ define "action.description" }}{{ .Action.Description }}{{ end }}`)
+
+	actionBuilder.WriteString(`{{ define "action.inputsHeader" }}## Inputs{{ end }}`)
+	actionBuilder.WriteString(`{{ define "action.inputsTable" }}`)
+	actionBuilder.WriteString(`{{ template "docs.table" (dict "Headers" (list "Input" "Required" "Default" "Description")`)
+	actionBuilder.WriteString(` "Columns" (list "key" "required" "default" "description") "Values" .Action.Inputs "LongDefaultStyle" .LongDefaultStyle) }}`)
+	actionBuilder.WriteString("{{ end }}")
+	actionBuilder.WriteString(`{{ define "action.inputsSection" }}`)
+	actionBuilder.WriteString(`{{ if .Action.Inputs }}{{ template "action.inputsHeader" . }}`)
+	actionBuilder.WriteString("\n\n")
+	actionBuilder.WriteString(`{{ template "action.inputsTable" . }}{{ end }}`)
+	actionBuilder.WriteString("{{ end }}")
+
+	actionBuilder.WriteString(`{{ define "action.outputsHeader" }}## Outputs{{ end }}`)
+	actionBuilder.WriteString(`{{ define "action.outputsTable" }}`)
+	actionBuilder.WriteString(`{{ template "docs.table" (dict "Headers" (list "Output" "Description") "Columns" (list "key" "description")`)
+	actionBuilder.WriteString(` "Values" .Action.Outputs "LongDefaultStyle" .LongDefaultStyle) }}`)
+	actionBuilder.WriteString("{{ end }}")
+	actionBuilder.WriteString(`{{ define "action.outputsSection" }}`)
+	actionBuilder.WriteString(`{{ if .Action.Outputs }}{{ template "action.outputsHeader" . }}`)
+	actionBuilder.WriteString("\n\n")
+	actionBuilder.WriteString(`{{ template "action.outputsTable" . }}{{ end }}`)
+	actionBuilder.WriteString("{{ end }}")
+
+	// Every input is listed in the snippet with its description as a comment, those with a default set to it
+	actionBuilder.WriteString(fmt.Sprintf(`{{ define "action.uses" }}%s{{ end }}`, actionUsesPlaceholder))
+	actionBuilder.WriteString(`{{ define "action.usageHeader" }}## Usage{{ end }}`)
+	actionBuilder.WriteString(`{{ define "action.usage" }}`)
+	actionBuilder.WriteString("```yaml\n")
+	actionBuilder.WriteString(`- uses: {{ template "action.uses" . }}`)
+	actionBuilder.WriteString("{{ if .Action.Inputs }}\n  with:{{ end }}")
+	actionBuilder.WriteString("  {{- range .Action.Inputs }}")
+	actionBuilder.WriteString("    {{- if .Description }}{{ range splitList \"\\n\" .Description }}\n    #{{ if . }} {{ . }}{{ end }}{{ end }}{{ end }}")
+	actionBuilder.WriteString("    {{- if .Required }}\n    # Required.{{ end }}")
+	actionBuilder.WriteString("\n    {{ .Key }}: {{ if .Default }}{{ quoteYamlDefault .Default 4 }}{{ else }}''{{ end }}")
+	actionBuilder.WriteString("  {{- end }}")
+	actionBuilder.WriteString("\n```")
+	actionBuilder.WriteString("{{ end }}")
+	actionBuilder.WriteString(`{{ define "action.usageSection" }}`)
+	actionBuilder.WriteString(`{{ template "action.usageHeader" . }}`)
+	actionBuilder.WriteString("\n\n")
+	actionBuilder.WriteString(`{{ template "action.usage" . }}`)
+	actionBuilder.WriteString("{{ end }}")
+
+	return actionBuilder.String()
+}
+
+// Templates rendering the services of compose files as a reference of their image, ports, volumes, environment
+// variables and dependencies, with a section for each service
+func getComposeTemplates() string {
+	composeBuilder := strings.Builder{}
+	composeBuilder.WriteString(`{{ define "compose.header" }}## {{ .Name }}{{ end }}`)
+	composeBuilder.WriteString(`{{ define "compose.description" }}{{ .Description }}{{ end }}`)
+
+	composeBuilder.WriteString(`{{ define "compose.summary" }}`)
+	composeBuilder.WriteString("{{ if .Image }}- **Image:** `{{ .Image }}`\n{{ end }}")
+	composeBuilder.WriteString("{{ if .Build }}- **Build:** `{{ .Build }}`\n{{ end }}")
+	composeBuilder.WriteString("{{ if .DependsOn }}- **Depends on:** ")
+	composeBuilder.WriteString("{{ range $i, $dependency := .DependsOn }}{{ if $i }}, {{ end }}`{{ .Name }}`{{ if .Condition }} ({{ .Condition }}){{ end }}{{ end }}\n")
+	composeBuilder.WriteString("{{ end }}")
+	composeBuilder.WriteString("{{ end }}")
+
+	composeBuilder.WriteString(`{{ define "compose.portsHeader" }}### Ports{{ end }}`)
+	composeBuilder.WriteString(`{{ define "compose.portsTable" }}`)
+	composeBuilder.WriteString("| Host | Container | Protocol |\n")
+	composeBuilder.WriteString("|------|-----------|----------|\n")
+	composeBuilder.WriteString("  {{- range .Ports }}")
+	composeBuilder.WriteString("\n| {{ if .HostIP }}{{ .HostIP }}:{{ end }}{{ .Published }} | {{ .Target }} | {{ default \"tcp\" .Protocol }} |")
+	composeBuilder.WriteString("  {{- end }}")
+	composeBuilder.WriteString("{{ end }}")
+
+	composeBuilder.WriteString(`{{ define "compose.volumesHeader" }}### Volumes{{ end }}`)
+	composeBuilder.WriteString(`{{ define "compose.volumesTable" }}`)
+	composeBuilder.WriteString("| Source | Target | Mode |\n")
+	composeBuilder.WriteString("|--------|--------|------|\n")
+	composeBuilder.WriteString("  {{- range .Volumes }}")
+	composeBuilder.WriteString("\n| {{ escapeKey .Source }} | {{ escapeKey .Target }} | {{ default \"rw\" .Mode }} |")
+	composeBuilder.WriteString("  {{- end }}")
+	composeBuilder.WriteString("{{ end }}")
+
+	composeBuilder.WriteString(`{{ define "compose.environmentHeader" }}### Environment{{ end }}`)
+	composeBuilder.WriteString(`{{ define "compose.environmentTable" }}`)
+	composeBuilder.WriteString(`{{ template "docs.table" (dict "Headers" (list "Variable" "Value" "Description") "Columns" (list "key" "default" "description")`)
+	composeBuilder.WriteString(` "Values" .Values "LongDefaultStyle" .LongDefaultStyle) }}`)
+	composeBuilder.WriteString("{{ end }}")
+
+	composeBuilder.WriteString(`{{ define "compose.section" }}`)
+	composeBuilder.WriteString(`{{ template "compose.header" . }}`)
+	composeBuilder.WriteString("\n\n")
+	composeBuilder.WriteString(`{{ if .Description }}{{ template "compose.description" . }}`)
+	composeBuilder.WriteString("\n\n{{ end }}")
+	composeBuilder.WriteString(`{{ template "compose.summary" . }}`)
+	composeBuilder.WriteString(`{{ if .Ports }}`)
+	composeBuilder.WriteString("\n")
+	composeBuilder.WriteString(`{{ template "compose.portsHeader" . }}`)
+	composeBuilder.WriteString("\n\n")
+	composeBuilder.WriteString(`{{ template "compose.portsTable" . }}`)
+	composeBuilder.WriteString("\n{{ end }}")
+	composeBuilder.WriteString(`{{ if .Volumes }}`)
+	composeBuilder.WriteString("\n")
+	composeBuilder.WriteString(`{{ template "compose.volumesHeader" . }}`)
+	composeBuilder.WriteString("\n\n")
+	composeBuilder.WriteString(`{{ template "compose.volumesTable" . }}`)
+	composeBuilder.WriteString("\n{{ end }}")
+	composeBuilder.WriteString(`{{ if .Values }}`)
+	composeBuilder.WriteString("\n")
+	composeBuilder.WriteString(`{{ template "compose.environmentHeader" . }}`)
+	composeBuilder.WriteString("\n\n")
+	composeBuilder.WriteString(`{{ template "compose.environmentTable" . }}`)
+	composeBuilder.WriteString("\n{{ end }}")
+	composeBuilder.WriteString("{{ end }}")
+
+	composeBuilder.WriteString(`{{ define "compose.servicesSection" }}`)
+	composeBuilder.WriteString(`{{ range $index, $service := .ComposeServices }}{{ if $index }}`)
+	composeBuilder.WriteString("\n{{ end }}")
+	composeBuilder.WriteString(`{{ template "compose.section" . }}{{ end }}`)
+	composeBuilder.WriteString("{{ end }}")
+
+	return composeBuilder.String()
+}
+
 func getYamlDocsVersionTemplates() string {
 	versionSectionBuilder := strings.Builder{}
 	versionSectionBuilder.WriteString(`{{ define "yaml-docs.version" }}{{ if .YamlDocsVersion }}{{ .YamlDocsVersion }}{{ end }}{{ end }}`)
@@ -51,22 +460,31 @@ func getYamlDocsVersionTemplates() string {
 	versionSectionBuilder.WriteString("Autogenerated using [yaml-docs v{{ .YamlDocsVersion }}](https://https://github.com/theEndBeta/yaml-docs/releases/v{{ .YamlDocsVersion }})")
 	versionSectionBuilder.WriteString("{{ end }}")
 	versionSectionBuilder.WriteString("{{ end }}")
+	versionSectionBuilder.WriteString(`{{ define "helm-docs.versionFooter" }}{{ template "yaml-docs.versionFooter" . }}{{ end }}`)
 
 	return versionSectionBuilder.String()
 }
 
-func getDocumentationTemplate(templateFiles []string) (string, error) {
+func getDocumentationTemplate(fsys fs.FS, templateFiles []string, profile string) (string, []diagnostic.Diagnostic, error) {
 	templateFilesForChart := make([]string, 0)
+	warnings := make([]diagnostic.Diagnostic, 0)
 
-	var templateNotFound bool
+	// Without any template files, there's nothing but the default template to render
+	templateNotFound := len(templateFiles) == 0
 
 	for _, templateFile := range templateFiles {
 		var fullTemplatePath string
 
 		fullTemplatePath = templateFile
 
-		if _, err := os.Stat(fullTemplatePath); os.IsNotExist(err) {
+		if _, err := fs.Stat(fsys, fullTemplatePath); errors.Is(err, fs.ErrNotExist) {
 			log.Debugf("Did not find template file %s, using default template", templateFile)
+			warnings = append(warnings, diagnostic.Diagnostic{
+				File:     templateFile,
+				Rule:     diagnostic.MissingTemplateRule,
+				Severity: diagnostic.SeverityWarning,
+				Message:  "template file not found, using the default template",
+			})
 
 			templateNotFound = true
 			continue
@@ -78,58 +496,60 @@ func getDocumentationTemplate(templateFiles []string) (string, error) {
 	log.Debugf("Using template files %s", templateFiles)
 	allTemplateContents := make([]byte, 0)
 	for _, templateFileForChart := range templateFilesForChart {
-		templateContents, err := ioutil.ReadFile(templateFileForChart)
+		templateContents, err := fs.ReadFile(fsys, templateFileForChart)
 		if err != nil {
-			return "", err
+			return "", nil, err
 		}
 		allTemplateContents = append(allTemplateContents, templateContents...)
 	}
 
 	if templateNotFound {
-		allTemplateContents = append(allTemplateContents, []byte(defaultDocumentationTemplate)...)
+		allTemplateContents = append(allTemplateContents, []byte(getDefaultDocumentationTemplate(profile))...)
 	}
 
-	return string(allTemplateContents), nil
+	return string(allTemplateContents), warnings, nil
 }
 
-func getDocumentationTemplates(templateFiles []string) ([]string, error) {
-	documentationTemplate, err := getDocumentationTemplate(templateFiles)
+func getDocumentationTemplates(fsys fs.FS, templateFiles []string, profile string) ([]string, []diagnostic.Diagnostic, error) {
+	documentationTemplate, warnings, err := getDocumentationTemplate(fsys, templateFiles, profile)
 
 	if err != nil {
-		log.Errorf("Failed to read documentation templates %s: %s", templateFiles, err)
-		return nil, err
+		return nil, nil, fmt.Errorf("failed to read documentation templates %s: %w", templateFiles, err)
 	}
 
 	return []string{
 		getValuesTableTemplates(),
+		getValuesTreeTemplates(),
+		getChartTemplates(),
+		getCustomResourceTemplates(),
+		getActionTemplates(),
+		getComposeTemplates(),
 		getYamlDocsVersionTemplates(),
 		documentationTemplate,
-	}, nil
+	}, warnings, nil
 }
 
+const documentationTemplateName = "documentation"
 
-func newDocumentationTemplate(templateFiles []string) (*template.Template, error) {
-	cwd, err := os.Getwd()
-	if err != nil {
-		return nil, err
-	}
-
-	documentationTemplate := template.New(path.Base(cwd))
+func newDocumentationTemplate(fsys fs.FS, templateFiles []string, profile string, escapeFormat string) (*template.Template, []diagnostic.Diagnostic, error) {
+	// The name only appears in template errors, it is fixed so that rendering never depends on the host
+	documentationTemplate := template.New(documentationTemplateName)
 	documentationTemplate.Funcs(sprig.TxtFuncMap())
+	documentationTemplate.Funcs(getEscapeFuncMap(escapeFormat))
 
-	goTemplateList, err := getDocumentationTemplates(templateFiles)
+	goTemplateList, warnings, err := getDocumentationTemplates(fsys, templateFiles, profile)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	for _, t := range goTemplateList {
 		_, err := documentationTemplate.Parse(t)
 
 		if err != nil {
-			return nil, err
+			return nil, nil, &TemplateError{Err: err}
 		}
 	}
 
-	return documentationTemplate, nil
+	return documentationTemplate, warnings, nil
 }
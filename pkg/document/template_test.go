@@ -2,27 +2,49 @@ package document
 
 import (
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/theEndBeta/yaml-docs/pkg/util"
 )
 
 func TestGetDocumentationTemplate(t *testing.T) {
-	tpl, err := getDocumentationTemplate([]string{"testdata/nonexistent.md.gotmpl"})
+	tpl, _, err := getDocumentationTemplate(util.OSFileSystem, []string{"testdata/nonexistent.md.gotmpl"}, ValuesProfile)
 
 	require.NoError(t, err)
 	assert.Equal(t, defaultDocumentationTemplate, tpl)
 }
 
 func TestGetDocumentationTemplate_LoadDefaultOnNotFound(t *testing.T) {
-	tpl, err := getDocumentationTemplate([]string{
+	tpl, _, err := getDocumentationTemplate(util.OSFileSystem, []string{
 		"testdata/README.md.gotmpl",
 		"testdata/nonexistent.md.gotmpl",
 		"testdata/README2.md.gotmpl",
-	})
+	}, ValuesProfile)
 
 	const expected = "hello\nhello again\n" + defaultDocumentationTemplate
 
 	require.NoError(t, err)
 	assert.Equal(t, expected, tpl)
 }
+
+func TestGetDocumentationTemplate_ProfileDefault(t *testing.T) {
+	tpl, _, err := getDocumentationTemplate(util.OSFileSystem, []string{"testdata/nonexistent.md.gotmpl"}, CRDProfile)
+
+	require.NoError(t, err)
+	assert.Equal(t, defaultCustomResourcesTemplate, tpl)
+}
+
+func TestNewDocumentationTemplate_InvalidTemplate(t *testing.T) {
+	fsys := fstest.MapFS{
+		"README.md.gotmpl": {Data: []byte("{{ .Values")},
+	}
+
+	_, _, err := newDocumentationTemplate(fsys, []string{"README.md.gotmpl"}, ValuesProfile, GitHubEscapeFormat)
+
+	var templateError *TemplateError
+	require.ErrorAs(t, err, &templateError)
+	assert.Contains(t, err.Error(), "template: documentation:1:")
+}
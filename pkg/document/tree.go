@@ -0,0 +1,133 @@
+package document
+
+import (
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A ValueTreeNode mirrors a single key of the values file, keeping the nesting of the yaml that the flat values table
+// loses. Nodes only exist for documented keys and the objects and lists containing them.
+// Long defaults are rendered like in the values table, so nodes keep the index of their row in the values table for
+// footnote labels, and the long default style that the tree templates can't get from the root.
+type ValueTreeNode struct {
+	Name             string
+	Key              string
+	Type             string
+	Default          string
+	LongDefault      string
+	Description      string
+	Documented       bool
+	Depth            int
+	Index            int
+	LongDefaultStyle string
+	Children         []ValueTreeNode
+}
+
+func getValueRowsByKey(valueRows []ValueRow) map[string]ValueRow {
+	rowsByKey := make(map[string]ValueRow, len(valueRows))
+
+	for _, row := range valueRows {
+		rowsByKey[row.Key] = row
+	}
+
+	return rowsByKey
+}
+
+type valueTreeContext struct {
+	rowIndexes       map[string]int
+	valueRows        []ValueRow
+	sortOrder        string
+	longDefaultStyle string
+}
+
+func newValueTreeContext(valueRows []ValueRow, options Options) valueTreeContext {
+	rowIndexes := make(map[string]int, len(valueRows))
+
+	for i, row := range valueRows {
+		rowIndexes[row.Key] = i
+	}
+
+	return valueTreeContext{
+		rowIndexes:       rowIndexes,
+		valueRows:        valueRows,
+		sortOrder:        options.SortValuesOrder,
+		longDefaultStyle: options.LongDefaultStyle,
+	}
+}
+
+func createValueTreeNode(name string, key string, value *yaml.Node, context valueTreeContext, depth int) (ValueTreeNode, bool) {
+	if value.Kind == yaml.AliasNode {
+		value = value.Alias
+	}
+
+	node := ValueTreeNode{
+		Name:             name,
+		Key:              key,
+		Depth:            depth,
+		LongDefaultStyle: context.longDefaultStyle,
+		Children:         createValueTreeNodes(key, value, context, depth+1),
+	}
+
+	if index, ok := context.rowIndexes[key]; ok {
+		row := context.valueRows[index]
+		node.Type = row.Type
+		node.Default = row.Default
+		node.LongDefault = row.LongDefault
+		node.Description = row.Description
+		node.Documented = true
+		node.Index = index
+		return node, true
+	}
+
+	if len(node.Children) == 0 {
+		return node, false
+	}
+
+	if value.Kind == yaml.SequenceNode {
+		node.Type = listType
+	} else {
+		node.Type = objectType
+	}
+
+	return node, true
+}
+
+func createValueTreeNodes(prefix string, value *yaml.Node, context valueTreeContext, depth int) []ValueTreeNode {
+	nodes := make([]ValueTreeNode, 0)
+
+	switch value.Kind {
+	case yaml.MappingNode:
+		for i := 0; i < len(value.Content); i += 2 {
+			k := value.Content[i]
+			v := value.Content[i+1]
+			key := formatNextObjectKeyPrefix(prefix, k.Value)
+
+			if node, ok := createValueTreeNode(k.Value, key, v, context, depth); ok {
+				nodes = append(nodes, node)
+			}
+		}
+
+		// List items always keep their index order, only object keys are sorted
+		if context.sortOrder != FileSortOrder {
+			sort.SliceStable(nodes, func(i, j int) bool {
+				return nodes[i].Name < nodes[j].Name
+			})
+		}
+	case yaml.SequenceNode:
+		for i, v := range value.Content {
+			key := formatNextListKeyPrefix(prefix, i)
+			name := formatNextListKeyPrefix("", i)
+
+			if node, ok := createValueTreeNode(name, key, v, context, depth); ok {
+				nodes = append(nodes, node)
+			}
+		}
+	}
+
+	return nodes
+}
+
+func getValuesTree(documentRoot *yaml.Node, valueRows []ValueRow, options Options) []ValueTreeNode {
+	return createValueTreeNodes("", documentRoot, newValueTreeContext(valueRows, options), 0)
+}
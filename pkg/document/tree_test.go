@@ -0,0 +1,114 @@
+package document
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/theEndBeta/yaml-docs/pkg/util"
+)
+
+func TestValuesTree(t *testing.T) {
+	yamlValues := parseYamlValues(`
+controller:
+  # -- Number of replicas
+  replicas: 2
+  # -- Configure the healthcheck
+  livenessProbe:
+    path: /healthz
+    port: http
+  args: [--verbose]
+alpha: true
+	`)
+
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
+	require.NoError(t, err)
+
+	tree := getValuesTree(yamlValues, valuesRows, Options{})
+
+	expected := []ValueTreeNode{
+		{Name: "alpha", Key: "alpha", Type: boolType, Default: "`true`", Documented: true, Children: []ValueTreeNode{}},
+		{
+			Name: "controller",
+			Key:  "controller",
+			Type: objectType,
+			Children: []ValueTreeNode{
+				{
+					Name:  "args",
+					Key:   "controller.args",
+					Type:  listType,
+					Depth: 1,
+					Children: []ValueTreeNode{
+						{Name: "[0]", Key: "controller.args[0]", Type: stringType, Default: "`\"--verbose\"`", Documented: true, Depth: 2, Index: 1, Children: []ValueTreeNode{}},
+					},
+				},
+				{
+					Name:        "livenessProbe",
+					Key:         "controller.livenessProbe",
+					Type:        objectType,
+					Default:     "`{\"path\":\"/healthz\",\"port\":\"http\"}`",
+					Description: "Configure the healthcheck",
+					Documented:  true,
+					Depth:       1,
+					Index:       2,
+					Children:    []ValueTreeNode{},
+				},
+				{Name: "replicas", Key: "controller.replicas", Type: intType, Default: "`2`", Description: "Number of replicas", Documented: true, Depth: 1, Index: 3, Children: []ValueTreeNode{}},
+			},
+		},
+	}
+
+	assert.Equal(t, expected, tree)
+}
+
+func TestValuesTreeFileOrder(t *testing.T) {
+	yamlValues := parseYamlValues(`
+zulu: 1
+alpha: 2
+	`)
+
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
+	require.NoError(t, err)
+
+	tree := getValuesTree(yamlValues, valuesRows, Options{SortValuesOrder: FileSortOrder})
+
+	require.Len(t, tree, 2)
+	assert.Equal(t, "zulu", tree[0].Name)
+	assert.Equal(t, "alpha", tree[1].Name)
+}
+
+func TestValuesTreeLongDefaults(t *testing.T) {
+	documentationTemplate, _, err := newDocumentationTemplate(util.OSFileSystem, []string{"testdata/nonexistent.md.gotmpl"}, ValuesProfile, GitHubEscapeFormat)
+	require.NoError(t, err)
+
+	yamlValues := parseYamlValues(`
+image: nginx
+# -- Volumes
+volumes:
+  - name: data
+	`)
+
+	for style, expected := range map[string]string{
+		DetailsLongDefaultStyle: "\n- **image** (`string`, default: `\"nginx\"`)" +
+			"\n- **volumes** (`list`, default: <details><summary>`[{\"name\":\"…`</summary><pre lang=\"yaml\">- name: data</pre></details>) Volumes",
+		FootnoteLongDefaultStyle: "\n- **image** (`string`, default: `\"nginx\"`)" +
+			"\n- **volumes** (`list`, default: `[{\"name\":\"…`[^default-1]) Volumes\n" +
+			"\n" +
+			"[^default-1]: Default value of volumes:\n" +
+			"\n" +
+			"    ```yaml\n" +
+			"    - name: data\n" +
+			"    ```",
+	} {
+		templateData, err := getTemplateData(&yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{yamlValues}}, Options{DefaultMaxLength: 10, LongDefaultStyle: style})
+		require.NoError(t, err)
+
+		var output bytes.Buffer
+		err = documentationTemplate.ExecuteTemplate(&output, "docs.valuesTree", templateData)
+		require.NoError(t, err)
+		assert.Equal(t, expected, output.String(), style)
+	}
+}
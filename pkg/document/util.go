@@ -1,6 +1,8 @@
 package document
 
 import (
+	"strings"
+
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
 )
@@ -10,6 +12,35 @@ const (
 	FileSortOrder     = "file"
 )
 
+const (
+	ValuesProfile  = "values"
+	CRDProfile     = "crd"
+	ActionProfile  = "action"
+	ComposeProfile = "compose"
+)
+
+const (
+	MarkdownOutputFormat   = "markdown"
+	CSVOutputFormat        = "csv"
+	TSVOutputFormat        = "tsv"
+	ManOutputFormat        = "man"
+	ConfluenceOutputFormat = "confluence"
+)
+
+// Defaults generated from the values file are rendered as markdown code spans, formats other than markdown want the
+// bare value instead
+func plainDefault(defaultValue string) string {
+	if len(defaultValue) >= 2 && strings.HasPrefix(defaultValue, "`") && strings.HasSuffix(defaultValue, "`") {
+		return defaultValue[1 : len(defaultValue)-1]
+	}
+
+	return defaultValue
+}
+
+func isCodeSpan(defaultValue string) bool {
+	return plainDefault(defaultValue) != defaultValue
+}
+
 // The json library can only marshal maps with string keys, and so all of our lists and maps that go into documentation
 // must be converted to have only string keys before marshalling
 func convertHelmValuesToJsonable(values *yaml.Node) interface{} {
@@ -56,6 +56,16 @@ func formatNextObjectKeyPrefix(prefix string, key string) string {
 	return nextPrefix
 }
 
+// FormatKey returns the key of a value as it is shown in the values table, given the keys of the objects leading to it
+func FormatKey(path []string) string {
+	key := ""
+	for _, name := range path {
+		key = formatNextObjectKeyPrefix(key, name)
+	}
+
+	return key
+}
+
 func getTypeName(value interface{}) string {
 	switch value.(type) {
 	case bool:
@@ -75,7 +85,7 @@ func getTypeName(value interface{}) string {
 	return ""
 }
 
-func parseNilValueType(key string, autoDescription helm.ValueDescription, column int, lineNumber int) valueRow {
+func parseNilValueType(key string, autoDescription helm.ValueDescription, column int, lineNumber int) ValueRow {
 	// Grab whatever's in between the parentheses of the description and treat it as the type
 	t := nilValueTypeRegex.FindString(autoDescription.Description)
 
@@ -91,7 +101,7 @@ func parseNilValueType(key string, autoDescription helm.ValueDescription, column
 		autoDescription.Default = "`nil`"
 	}
 
-	return valueRow{
+	return ValueRow{
 		Key:             key,
 		Type:            t,
 		Default:         autoDescription.Default,
@@ -138,25 +148,29 @@ func createValueRow(
 	autoDescription helm.ValueDescription,
 	column int,
 	lineNumber int,
-) (valueRow, error) {
+	options Options,
+) (ValueRow, error) {
 	if value == nil {
 		return parseNilValueType(key, autoDescription, column, lineNumber), nil
 	}
 
 	defaultValue := autoDescription.Default
+	longDefaultValue := ""
 	if defaultValue == "" {
-		jsonEncodedValue, err := jsonMarshalNoEscape(key, value)
+		formattedValue, longValue, err := formatDefaultValue(key, value, options.DefaultFormat, options.DefaultMaxLength)
 		if err != nil {
-			return valueRow{}, fmt.Errorf("failed to marshal default value for %s to json: %s", key, err)
+			return ValueRow{}, err
 		}
 
-		defaultValue = fmt.Sprintf("`%s`", jsonEncodedValue)
+		defaultValue = fmt.Sprintf("`%s`", formattedValue)
+		longDefaultValue = longValue
 	}
 
-	return valueRow{
+	return ValueRow{
 		Key:         key,
 		Type:        getTypeName(value),
 		Default:     defaultValue,
+		LongDefault: longDefaultValue,
 		Description: autoDescription.Description,
 		Column:      column,
 		LineNumber:  lineNumber,
@@ -168,31 +182,32 @@ func createValueRowsFromList(
 	key *yaml.Node,
 	values *yaml.Node,
 	documentLeafNodes bool,
-) ([]valueRow, error) {
+	options Options,
+) ([]ValueRow, error) {
 	autoDescription := getDescriptionFromNode(key)
 
 	// If we encounter an empty list, it should be documented if no parent object or list had a description or if this
 	// list has a description
 	if len(values.Content) == 0 {
 		if !(documentLeafNodes  || autoDescription.Description != "") {
-			return []valueRow{}, nil
+			return []ValueRow{}, nil
 		}
 
-		emptyListRow, err := createValueRow(prefix, make([]interface{}, 0), autoDescription, key.Column, key.Line)
+		emptyListRow, err := createValueRow(prefix, make([]interface{}, 0), autoDescription, key.Column, key.Line, options)
 		if err != nil {
 			return nil, err
 		}
 
-		return []valueRow{emptyListRow}, nil
+		return []ValueRow{emptyListRow}, nil
 	}
 
-	valueRows := make([]valueRow, 0)
+	valueRows := make([]ValueRow, 0)
 
 	// We have a nonempty list with a description, document it, and mark that leaf nodes underneath it should not be
 	// documented without descriptions
 	if autoDescription.Description != "" {
 		jsonableObject := convertHelmValuesToJsonable(values)
-		listRow, err := createValueRow(prefix, jsonableObject, autoDescription, key.Column, key.Line)
+		listRow, err := createValueRow(prefix, jsonableObject, autoDescription, key.Column, key.Line, options)
 
 		if err != nil {
 			return nil, err
@@ -205,7 +220,7 @@ func createValueRowsFromList(
 	// Generate documentation rows for all list items and their potential sub-fields
 	for i, v := range values.Content {
 		nextPrefix := formatNextListKeyPrefix(prefix, i)
-		valueRowsForListField, err := createValueRowsFromField(nextPrefix, v, v, documentLeafNodes)
+		valueRowsForListField, err := createValueRowsFromField(nextPrefix, v, v, documentLeafNodes, options)
 
 		if err != nil {
 			return nil, err
@@ -222,32 +237,33 @@ func createValueRowsFromObject(
 	key *yaml.Node,
 	values *yaml.Node,
 	documentLeafNodes bool,
-) ([]valueRow, error) {
+	options Options,
+) ([]ValueRow, error) {
 	autoDescription := getDescriptionFromNode(key)
 
 	if len(values.Content) == 0 {
 		// if the first level of recursion has no values, then there are no values at all, and so we return zero rows of documentation
 		if nextPrefix == "" {
-			return []valueRow{}, nil
+			return []ValueRow{}, nil
 		}
 
 		// Otherwise, we have a leaf empty object node that should be documented if no object up the recursion chain had
 		// a description or if this object has a description
 		if !(documentLeafNodes || autoDescription.Description != "") {
-			return []valueRow{}, nil
+			return []ValueRow{}, nil
 		}
 
-		documentedRow, err := createValueRow(nextPrefix, make(map[string]interface{}), autoDescription, key.Column, key.Line)
-		return []valueRow{documentedRow}, err
+		documentedRow, err := createValueRow(nextPrefix, make(map[string]interface{}), autoDescription, key.Column, key.Line, options)
+		return []ValueRow{documentedRow}, err
 	}
 
-	valueRows := make([]valueRow, 0)
+	valueRows := make([]ValueRow, 0)
 
 	// We have a nonempty object with a description, document it, and mark that leaf nodes underneath it should not be
 	// documented without descriptions
 	if autoDescription.Description != "" {
 		jsonableObject := convertHelmValuesToJsonable(values)
-		objectRow, err := createValueRow(nextPrefix, jsonableObject, autoDescription, key.Column, key.Line)
+		objectRow, err := createValueRow(nextPrefix, jsonableObject, autoDescription, key.Column, key.Line, options)
 
 		if err != nil {
 			return nil, err
@@ -261,7 +277,7 @@ func createValueRowsFromObject(
 		k := values.Content[i]
 		v := values.Content[i+1]
 		nextPrefix := formatNextObjectKeyPrefix(nextPrefix, k.Value)
-		valueRowsForObjectField, err := createValueRowsFromField(nextPrefix, k, v, documentLeafNodes)
+		valueRowsForObjectField, err := createValueRowsFromField(nextPrefix, k, v, documentLeafNodes, options)
 
 		if err != nil {
 			return nil, err
@@ -278,57 +294,89 @@ func createValueRowsFromField(
 	key *yaml.Node,
 	value *yaml.Node,
 	documentLeafNodes bool,
-) ([]valueRow, error) {
+	options Options,
+) ([]ValueRow, error) {
 	switch value.Kind {
 	case yaml.MappingNode:
-		return createValueRowsFromObject(prefix, key, value, documentLeafNodes)
+		return createValueRowsFromObject(prefix, key, value, documentLeafNodes, options)
 	case yaml.SequenceNode:
-		return createValueRowsFromList(prefix, key, value, documentLeafNodes)
+		return createValueRowsFromList(prefix, key, value, documentLeafNodes, options)
 	case yaml.AliasNode:
-		return createValueRowsFromField(prefix, key, value.Alias, documentLeafNodes)
+		return createValueRowsFromField(prefix, key, value.Alias, documentLeafNodes, options)
 	case yaml.ScalarNode:
 		autoDescription := getDescriptionFromNode(key)
 		if (!documentLeafNodes && autoDescription.Description == "") {
-			return []valueRow{}, nil
+			return []ValueRow{}, nil
 		}
 
 		switch value.Tag {
 		case nullTag:
-			leafValueRow, err := createValueRow(prefix, nil, autoDescription, key.Column, key.Line)
-			return []valueRow{leafValueRow}, err
+			leafValueRow, err := createValueRow(prefix, nil, autoDescription, key.Column, key.Line, options)
+			return []ValueRow{leafValueRow}, err
 		case strTag:
 			fallthrough
 		case timestampTag:
-			leafValueRow, err := createValueRow(prefix, value.Value, autoDescription, key.Column, key.Line)
-			return []valueRow{leafValueRow}, err
+			leafValueRow, err := createValueRow(prefix, value.Value, autoDescription, key.Column, key.Line, options)
+			return []ValueRow{leafValueRow}, err
 		case intTag:
 			var decodedValue int
 			err := value.Decode(&decodedValue)
 			if err != nil {
-				return []valueRow{}, err
+				return []ValueRow{}, err
 			}
 
-			leafValueRow, err := createValueRow(prefix, decodedValue, autoDescription, key.Column, key.Line)
-			return []valueRow{leafValueRow}, err
+			leafValueRow, err := createValueRow(prefix, decodedValue, autoDescription, key.Column, key.Line, options)
+			return []ValueRow{leafValueRow}, err
 		case floatTag:
 			var decodedValue float64
 			err := value.Decode(&decodedValue)
 			if err != nil {
-				return []valueRow{}, err
+				return []ValueRow{}, err
 			}
-			leafValueRow, err := createValueRow(prefix, decodedValue, autoDescription, key.Column, key.Line)
-			return []valueRow{leafValueRow}, err
+			leafValueRow, err := createValueRow(prefix, decodedValue, autoDescription, key.Column, key.Line, options)
+			return []ValueRow{leafValueRow}, err
 
 		case boolTag:
 			var decodedValue bool
 			err := value.Decode(&decodedValue)
 			if err != nil {
-				return []valueRow{}, err
+				return []ValueRow{}, err
 			}
-			leafValueRow, err := createValueRow(prefix, decodedValue, autoDescription, key.Column, key.Line)
-			return []valueRow{leafValueRow}, err
+			leafValueRow, err := createValueRow(prefix, decodedValue, autoDescription, key.Column, key.Line, options)
+			return []ValueRow{leafValueRow}, err
+		}
+	}
+
+	return []ValueRow{}, fmt.Errorf("invalid node type %d received", value.Kind)
+}
+
+func addKeyPaths(paths map[string]bool, prefix string, value *yaml.Node) {
+	switch value.Kind {
+	case yaml.AliasNode:
+		addKeyPaths(paths, prefix, value.Alias)
+	case yaml.MappingNode:
+		for i := 0; i < len(value.Content); i += 2 {
+			key := formatNextObjectKeyPrefix(prefix, value.Content[i].Value)
+			paths[key] = true
+			addKeyPaths(paths, key, value.Content[i+1])
+		}
+	case yaml.SequenceNode:
+		for i, item := range value.Content {
+			key := formatNextListKeyPrefix(prefix, i)
+			paths[key] = true
+			addKeyPaths(paths, key, item)
 		}
 	}
+}
+
+// KeyPaths returns the set of all keys in parsed values, including objects, lists and list items, formatted as they
+// are in the values table
+func KeyPaths(valuesData *yaml.Node) map[string]bool {
+	paths := make(map[string]bool)
+
+	for _, content := range valuesData.Content {
+		addKeyPaths(paths, "", content)
+	}
 
-	return []valueRow{}, fmt.Errorf("invalid node type %d received", value.Kind)
+	return paths
 }
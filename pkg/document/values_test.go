@@ -21,7 +21,7 @@ func parseYamlValues(yamlValues string) *yaml.Node {
 
 func TestEmptyValues(t *testing.T) {
 	yamlValues := parseYamlValues(`{}`)
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 0)
 }
@@ -34,7 +34,7 @@ hello: "world"
 oscar: 3.14159
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 4)
@@ -72,7 +72,7 @@ hello: "world"
 oscar: 3.14159
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 4)
@@ -114,7 +114,7 @@ hello: "world"
 oscar: 3.14159
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 4)
@@ -147,7 +147,7 @@ recursive:
 oscar: dog
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -172,7 +172,7 @@ recursive:
 oscar: dog
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -199,7 +199,7 @@ recursive:
 oscar: dog
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -221,7 +221,7 @@ recursive: {}
 oscar: dog
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -244,7 +244,7 @@ recursive: {}
 oscar: dog
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -268,7 +268,7 @@ recursive: {}
 oscar: dog
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -289,7 +289,7 @@ birds: []
 echo: cat
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -313,7 +313,7 @@ birds: []
 echo: cat
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -339,7 +339,7 @@ birds: []
 echo: cat
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -360,7 +360,7 @@ func TestListOfStrings(t *testing.T) {
 cats: [echo, foxtrot]
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -386,7 +386,7 @@ cats:
   - foxtrot
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -415,7 +415,7 @@ cats:
   - foxtrot
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -441,7 +441,7 @@ animals:
     type: dog
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 5)
@@ -488,7 +488,7 @@ animals:
     type: dog
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 5)
@@ -538,7 +538,7 @@ animals:
     type: dog
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 5)
@@ -579,7 +579,7 @@ animals:
     type: dog
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 1)
@@ -601,7 +601,7 @@ animals:
     type: dog
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 1)
@@ -623,7 +623,7 @@ animals:
     type: dog
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 3)
@@ -654,7 +654,7 @@ animals:
     sleepy: [oscar]
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 1)
@@ -676,7 +676,7 @@ animals:
     sleepy: [oscar]
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 1)
@@ -702,7 +702,7 @@ animals:
     sleepy: [oscar]
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 4)
@@ -747,7 +747,7 @@ animals:
     sleepy: [oscar]
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 4)
@@ -784,7 +784,7 @@ animals:
   nonWeirdCats:
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 3)
@@ -819,7 +819,7 @@ animals:
   nonWeirdCats:
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 3)
@@ -848,7 +848,7 @@ fullNames:
   John Norwood: me
 `)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -874,7 +874,7 @@ fullNames:
   John Norwood: me
 `)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -902,7 +902,7 @@ fullNames:
   John Norwood: me
 `)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -927,7 +927,7 @@ foxtrot: true
 hello: "world"
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -955,7 +955,7 @@ animals:
       - foxtrot
 `)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 1)
@@ -974,7 +974,7 @@ animals:
   dogs:
 `)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 1)
@@ -1003,7 +1003,7 @@ animals:
   porcupines:
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 7)
@@ -1049,7 +1049,7 @@ animals:
   fish:
 	`)
 
-	valuesRows, err := getSortedValuesTableRows(yamlValues)
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{})
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 3)
@@ -1069,3 +1069,27 @@ animals:
 	assert.Equal(t, "`nil`", valuesRows[2].Default)
 	assert.Equal(t, "can haz Mermen?", valuesRows[2].Description)
 }
+
+func TestFileSortOrder(t *testing.T) {
+	yamlValues := parseYamlValues(`
+zebra: 1
+apple:
+  mango: 2
+  banana: 3
+cherry: {kiwi: 4, fig: 5}
+`)
+
+	valuesRows, err := getSortedValuesTableRows(yamlValues, Options{SortValuesOrder: FileSortOrder})
+	assert.Nil(t, err)
+
+	keys := make([]string, 0, len(valuesRows))
+	for _, row := range valuesRows {
+		keys = append(keys, row.Key)
+	}
+
+	assert.Equal(t, []string{"zebra", "apple.mango", "apple.banana", "cherry.kiwi", "cherry.fig"}, keys)
+
+	rows := []ValueRow{{Key: "c", LineNumber: 3, Column: 1}, {Key: "b2", LineNumber: 2, Column: 9}, {Key: "b1", LineNumber: 2, Column: 3}, {Key: "a", LineNumber: 1, Column: 1}}
+	sortValueRows(rows, FileSortOrder)
+	assert.Equal(t, []string{"a", "b1", "b2", "c"}, []string{rows[0].Key, rows[1].Key, rows[2].Key, rows[3].Key})
+}
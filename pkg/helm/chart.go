@@ -0,0 +1,205 @@
+package helm
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+	"github.com/theEndBeta/yaml-docs/pkg/util"
+)
+
+// ChartFileName is the name of the file describing a chart, found next to its values file
+const ChartFileName = "Chart.yaml"
+
+// Files listing the dependencies of apiVersion v1 charts, and the versions dependencies were resolved to
+const (
+	requirementsFileName     = "requirements.yaml"
+	requirementsLockFileName = "requirements.lock"
+	chartLockFileName        = "Chart.lock"
+)
+
+// Maintainer is one of the maintainers listed in Chart.yaml
+type Maintainer struct {
+	Name  string `yaml:"name"`
+	Email string `yaml:"email"`
+	URL   string `yaml:"url"`
+}
+
+// Dependency is a chart that a chart depends on, from Chart.yaml or requirements.yaml
+type Dependency struct {
+	Name       string `yaml:"name"`
+	Repository string `yaml:"repository"`
+	Alias      string `yaml:"alias"`
+	Condition  string `yaml:"condition"`
+
+	// Version constraint the dependency must satisfy
+	Version string `yaml:"version"`
+
+	// Version the dependency was resolved to in Chart.lock or requirements.lock, empty without a lock file
+	ResolvedVersion string `yaml:"-"`
+}
+
+// Key returns the key that the parent chart sets the values of the dependency under, its alias or otherwise its name
+func (d Dependency) Key() string {
+	if d.Alias != "" {
+		return d.Alias
+	}
+
+	return d.Name
+}
+
+type dependenciesFile struct {
+	Dependencies []Dependency `yaml:"dependencies"`
+}
+
+// ChartMetadata is the part of Chart.yaml that is shown in documentation, for both apiVersion v1 and v2 charts
+type ChartMetadata struct {
+	APIVersion  string       `yaml:"apiVersion"`
+	Name        string       `yaml:"name"`
+	Version     string       `yaml:"version"`
+	AppVersion  string       `yaml:"appVersion"`
+	Type        string       `yaml:"type"`
+	Description string       `yaml:"description"`
+	Home        string       `yaml:"home"`
+	Sources     []string     `yaml:"sources"`
+	Maintainers []Maintainer `yaml:"maintainers"`
+	Keywords    []string     `yaml:"keywords"`
+	KubeVersion string       `yaml:"kubeVersion"`
+	Deprecated  bool         `yaml:"deprecated"`
+
+	// Dependencies of the chart, read from requirements.yaml for apiVersion v1 charts
+	Dependencies []Dependency `yaml:"dependencies"`
+}
+
+// Files of a chart are next to each other, and read from the same revision for files read from a git revision
+func siblingPath(filePath string, name string) string {
+	if revision, revisionPath, ok := util.ParseGitPath(filePath); ok {
+		return fmt.Sprintf("%s%s:%s", util.GitPathPrefix, revision, path.Join(path.Dir(revisionPath), name))
+	}
+
+	return path.Join(path.Dir(filePath), name)
+}
+
+// ChartFilePath returns the path of the Chart.yaml next to a values file. For values files read from a git revision,
+// Chart.yaml is read from the same revision.
+func ChartFilePath(valuesPath string) string {
+	return siblingPath(valuesPath, ChartFileName)
+}
+
+// ChartFilePaths returns the paths of all files next to a values file that chart metadata is read from
+func ChartFilePaths(valuesPath string) []string {
+	paths := make([]string, 0)
+	for _, name := range []string{ChartFileName, chartLockFileName, requirementsFileName, requirementsLockFileName} {
+		paths = append(paths, siblingPath(valuesPath, name))
+	}
+
+	return paths
+}
+
+// Reads the dependencies listed in a requirements.yaml or lock file, which are both optional
+func parseDependenciesFile(fsys fs.FS, filePath string) ([]Dependency, error) {
+	contents, err := fs.ReadFile(fsys, filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var dependencies dependenciesFile
+	if err := yaml.Unmarshal(normalizeLineEndings(contents), &dependencies); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+
+	return dependencies.Dependencies, nil
+}
+
+// Lock files list each dependency once by name and repository, even when it is used under several aliases
+func addResolvedVersions(dependencies []Dependency, locked []Dependency) {
+	for i := range dependencies {
+		for _, lockedDependency := range locked {
+			if lockedDependency.Name == dependencies[i].Name && lockedDependency.Repository == dependencies[i].Repository {
+				dependencies[i].ResolvedVersion = lockedDependency.Version
+				break
+			}
+		}
+	}
+}
+
+func dependenciesFileWarning(filePath string, err error) diagnostic.Diagnostic {
+	return diagnostic.Diagnostic{
+		File:     filePath,
+		Line:     diagnostic.LineFromYamlError(err),
+		Rule:     diagnostic.InvalidChartMetadataRule,
+		Severity: diagnostic.SeverityWarning,
+		Message:  fmt.Sprintf("ignoring invalid chart dependencies file: %s", err),
+	}
+}
+
+// The requirements.yaml and lock files only add to the metadata, so they're reported as warnings against their own file
+// when they can't be read, leaving the dependencies without them
+func parseChartDependencies(fsys fs.FS, chartPath string, chart *ChartMetadata) []diagnostic.Diagnostic {
+	lockFileName := chartLockFileName
+
+	if chart.APIVersion == "v1" {
+		requirementsPath := siblingPath(chartPath, requirementsFileName)
+		dependencies, err := parseDependenciesFile(fsys, requirementsPath)
+		if err != nil {
+			return []diagnostic.Diagnostic{dependenciesFileWarning(requirementsPath, err)}
+		}
+
+		if len(dependencies) > 0 {
+			chart.Dependencies = dependencies
+		}
+
+		lockFileName = requirementsLockFileName
+	}
+
+	if len(chart.Dependencies) == 0 {
+		return nil
+	}
+
+	lockPath := siblingPath(chartPath, lockFileName)
+	locked, err := parseDependenciesFile(fsys, lockPath)
+	if err != nil {
+		return []diagnostic.Diagnostic{dependenciesFileWarning(lockPath, err)}
+	}
+
+	addResolvedVersions(chart.Dependencies, locked)
+	return nil
+}
+
+// ParseChartMetadata reads and parses a Chart.yaml file, along with the requirements.yaml and lock file next to it.
+// Problems with the latter two are returned as warnings rather than failing the whole chart. The error wraps
+// fs.ErrNotExist if Chart.yaml doesn't exist.
+func ParseChartMetadata(fsys fs.FS, chartPath string) (ChartMetadata, []diagnostic.Diagnostic, error) {
+	var chart ChartMetadata
+
+	contents, err := fs.ReadFile(fsys, chartPath)
+	if err != nil {
+		return chart, nil, err
+	}
+
+	if err := yaml.Unmarshal(normalizeLineEndings(contents), &chart); err != nil {
+		return ChartMetadata{}, nil, fmt.Errorf("failed to parse %s: %w", chartPath, err)
+	}
+
+	switch chart.APIVersion {
+	case "v1", "v2":
+	case "":
+		return ChartMetadata{}, nil, fmt.Errorf("%s has no apiVersion", chartPath)
+	default:
+		return ChartMetadata{}, nil, fmt.Errorf("%s has unsupported apiVersion %s, expected v1 or v2", chartPath, chart.APIVersion)
+	}
+
+	if chart.Name == "" {
+		return ChartMetadata{}, nil, fmt.Errorf("%s has no name", chartPath)
+	}
+
+	return chart, parseChartDependencies(fsys, chartPath, &chart), nil
+}
@@ -2,13 +2,17 @@ package helm
 
 import (
 	"bufio"
-	"io/ioutil"
-	"os"
+	"errors"
+	"fmt"
+	"io/fs"
 	"regexp"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+	"github.com/theEndBeta/yaml-docs/pkg/util"
 )
 
 var valuesDescriptionRegex = regexp.MustCompile("^\\s*#\\s*(.*)\\s+--\\s*(.*)$")
@@ -25,50 +29,68 @@ type DocumentationInfo struct {
 	ValuesDescriptions map[string]ValueDescription
 }
 
-func getYamlFileContents(filename string) ([]byte, error) {
-	if _, err := os.Stat(filename); os.IsNotExist(err) {
-		return nil, err
-	}
+// FileError is returned when a values file can't be read
+type FileError struct {
+	Path string
+	Err  error
+}
 
-	yamlFileContents, err := ioutil.ReadFile(filename)
-	if err != nil {
-		panic(err)
+func (e *FileError) Error() string {
+	if errors.Is(e.Err, fs.ErrNotExist) {
+		return fmt.Sprintf("values file %s does not exist", e.Path)
 	}
 
-	return []byte(strings.Replace(string(yamlFileContents), "\r\n", "\n", -1)), nil
+	return fmt.Sprintf("failed to read values file %s: %s", e.Path, e.Err)
 }
 
-func isErrorInReadingNecessaryFile(filePath string, loadError error) bool {
-	if loadError != nil {
-		if os.IsNotExist(loadError) {
-			log.Warnf("Required chart file %s missing. Skipping documentation for chart", filePath)
-			return true
-		} else {
-			log.Warnf("Error occurred in reading chart file %s. Skipping documentation for chart", filePath)
-			return true
-		}
-	}
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// ParseError is returned when a values file isn't valid yaml
+type ParseError struct {
+	Path string
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("failed to parse values file %s: %s", e.Path, e.Err)
+}
 
-	return false
+func (e *ParseError) Unwrap() error {
+	return e.Err
 }
 
-func parseValuesFile(valuesPath string) (yaml.Node, error) {
-	yamlFileContents, err := getYamlFileContents(valuesPath)
+func normalizeLineEndings(contents []byte) []byte {
+	return []byte(strings.Replace(string(contents), "\r\n", "\n", -1))
+}
+
+func getYamlFileContents(fsys fs.FS, filename string) ([]byte, error) {
+	yamlFileContents, err := fs.ReadFile(fsys, filename)
+	if err != nil {
+		return nil, &FileError{Path: filename, Err: err}
+	}
+
+	return normalizeLineEndings(yamlFileContents), nil
+}
 
+func parseValuesContents(valuesPath string, contents []byte) (yaml.Node, error) {
 	var values yaml.Node
-	if isErrorInReadingNecessaryFile(valuesPath, err) {
-		return values, err
+
+	err := yaml.Unmarshal(contents, &values)
+	if err != nil {
+		return values, &ParseError{Path: valuesPath, Line: diagnostic.LineFromYamlError(err), Err: err}
 	}
 
-	err = yaml.Unmarshal(yamlFileContents, &values)
-	return values, err
+	return values, nil
 }
 
-func parseValuesFileComments(valuesPath string) (map[string]ValueDescription, error) {
-	valuesFile, err := os.Open(valuesPath)
+func parseValuesFileComments(fsys fs.FS, valuesPath string) (map[string]ValueDescription, error) {
+	valuesFile, err := fsys.Open(valuesPath)
 
-	if isErrorInReadingNecessaryFile(valuesPath, err) {
-		return map[string]ValueDescription{}, err
+	if err != nil {
+		return map[string]ValueDescription{}, &FileError{Path: valuesPath, Err: err}
 	}
 
 	defer valuesFile.Close()
@@ -138,27 +160,90 @@ func _joinValuesFiles(valuesNodes []yaml.Node) (yaml.Node) {
 	return mergedValues
 }
 
+// DiagnosticFromError describes an error reading or parsing a values file as a diagnostic, with the given severity. Other
+// errors are reported against diagnostic.ErrorRule.
+func DiagnosticFromError(err error, severity string) diagnostic.Diagnostic {
+	var parseError *ParseError
+	if errors.As(err, &parseError) {
+		return diagnostic.Diagnostic{
+			File:     parseError.Path,
+			Line:     parseError.Line,
+			Rule:     diagnostic.InvalidYamlRule,
+			Severity: severity,
+			Message:  fmt.Sprintf("invalid values file: %s", parseError.Err),
+		}
+	}
+
+	var fileError *FileError
+	if errors.As(err, &fileError) {
+		return diagnostic.Diagnostic{
+			File:     fileError.Path,
+			Rule:     diagnostic.UnreadableValuesFileRule,
+			Severity: severity,
+			Message:  fmt.Sprintf("unreadable values file: %s", fileError.Err),
+		}
+	}
+
+	return diagnostic.Diagnostic{Rule: diagnostic.ErrorRule, Severity: severity, Message: err.Error()}
+}
+
+// ParseValues parses and merges values files from the host filesystem, logging any warnings. Use ParseValuesFS to get
+// the warnings as diagnostics instead.
 func ParseValues(valuesFileNames []string) (*yaml.Node, error) {
+	values, warnings, err := ParseValuesFS(util.OSFileSystem, valuesFileNames)
+	for _, warning := range warnings {
+		log.Warn(warning.String())
+	}
+
+	return values, err
+}
 
-	valuesNodes := make([]yaml.Node, len(valuesFileNames))
+// ParseValuesFS parses and merges values files read from fsys. Files which can't be read or parsed are skipped and
+// reported as warnings along with problems found in the comments of the remaining files. An error is only returned if
+// none of the files could be parsed.
+func ParseValuesFS(fsys fs.FS, valuesFileNames []string) (*yaml.Node, []diagnostic.Diagnostic, error) {
+	valuesNodes := make([]yaml.Node, 0, len(valuesFileNames))
+	warnings := make([]diagnostic.Diagnostic, 0)
+	fileErrors := make([]error, 0)
 
-	for idx, valuesFile := range valuesFileNames {
-		values, err := parseValuesFile(valuesFile)
+	for _, valuesFile := range valuesFileNames {
+		contents, err := getYamlFileContents(fsys, valuesFile)
 		if err != nil {
-			log.Warnf("Error parsing values from file: %s", valuesFile)
-			values = yaml.Node{}
+			fileErrors = append(fileErrors, err)
+			continue
 		}
 
+		values, err := parseValuesContents(valuesFile, contents)
 		if err != nil {
-			log.Warnf("Error parsing values from file: %s", valuesFile)
-			values = yaml.Node{}
+			fileErrors = append(fileErrors, err)
+			continue
 		}
 
-		valuesNodes[idx] = values
+		warnings = append(warnings, checkComments(&values, valuesFile, getFileLines(contents))...)
+		valuesNodes = append(valuesNodes, values)
+	}
+
+	if len(valuesNodes) == 0 && len(fileErrors) > 0 {
+		return nil, warnings, fileErrors[0]
+	}
+
+	for _, err := range fileErrors {
+		warnings = append(warnings, DiagnosticFromError(err, diagnostic.SeverityWarning))
 	}
 
 	mergedValues := _joinValuesFiles(valuesNodes)
 
+	return &mergedValues, warnings, nil
+}
+
+// ParseValuesContents parses the contents of a single values file that isn't read from a filesystem, such as a file
+// open in an editor. Problems found in its comments are returned as warnings, invalid yaml as a ParseError.
+func ParseValuesContents(valuesPath string, contents []byte) (*yaml.Node, []diagnostic.Diagnostic, error) {
+	contents = normalizeLineEndings(contents)
+	values, err := parseValuesContents(valuesPath, contents)
+	if err != nil {
+		return nil, []diagnostic.Diagnostic{}, err
+	}
 
-	return &mergedValues, nil
+	return &values, checkComments(&values, valuesPath, getFileLines(contents)), nil
 }
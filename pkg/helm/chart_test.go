@@ -0,0 +1,162 @@
+package helm
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+)
+
+func TestParseChartMetadata(t *testing.T) {
+	fsys := fstest.MapFS{
+		"v2/Chart.yaml": {Data: []byte(`apiVersion: v2
+name: app
+version: 1.0.0
+appVersion: 13.0
+type: application
+home: https://example.com
+sources: ["https://example.com/src"]
+maintainers:
+  - name: Jane Doe
+    email: jane@example.com
+keywords: [web, proxy]
+kubeVersion: ">=1.18"
+deprecated: true
+`)},
+		"v1/Chart.yaml":          {Data: []byte("apiVersion: v1\r\nname: legacy\r\nversion: 0.1.0\r\n")},
+		"no-version/Chart.yaml":  {Data: []byte("name: app\n")},
+		"unsupported/Chart.yaml": {Data: []byte("apiVersion: v3\nname: app\n")},
+		"invalid/Chart.yaml":     {Data: []byte("name: [\n")},
+	}
+
+	chart, _, err := ParseChartMetadata(fsys, "v2/Chart.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, ChartMetadata{
+		APIVersion:  "v2",
+		Name:        "app",
+		Version:     "1.0.0",
+		AppVersion:  "13.0",
+		Type:        "application",
+		Home:        "https://example.com",
+		Sources:     []string{"https://example.com/src"},
+		Maintainers: []Maintainer{{Name: "Jane Doe", Email: "jane@example.com"}},
+		Keywords:    []string{"web", "proxy"},
+		KubeVersion: ">=1.18",
+		Deprecated:  true,
+	}, chart)
+
+	chart, _, err = ParseChartMetadata(fsys, "v1/Chart.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, ChartMetadata{APIVersion: "v1", Name: "legacy", Version: "0.1.0"}, chart)
+
+	_, _, err = ParseChartMetadata(fsys, "missing/Chart.yaml")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	for _, chartPath := range []string{"no-version/Chart.yaml", "unsupported/Chart.yaml", "invalid/Chart.yaml"} {
+		_, _, err = ParseChartMetadata(fsys, chartPath)
+		assert.Error(t, err, chartPath)
+		assert.NotErrorIs(t, err, fs.ErrNotExist, chartPath)
+	}
+}
+
+func TestChartFilePath(t *testing.T) {
+	assert.Equal(t, "Chart.yaml", ChartFilePath("values.yaml"))
+	assert.Equal(t, "charts/app/Chart.yaml", ChartFilePath("charts/app/values.yaml"))
+	assert.Equal(t, "git:v1.0.0:Chart.yaml", ChartFilePath("git:v1.0.0:values.yaml"))
+	assert.Equal(t, "git:HEAD~1:charts/app/Chart.yaml", ChartFilePath("git:HEAD~1:charts/app/values.yaml"))
+	assert.Equal(t, []string{"app/Chart.yaml", "app/Chart.lock", "app/requirements.yaml", "app/requirements.lock"}, ChartFilePaths("app/values.yaml"))
+}
+
+func TestParseChartDependencies(t *testing.T) {
+	fsys := fstest.MapFS{
+		"v2/Chart.yaml": {Data: []byte(`apiVersion: v2
+name: umbrella
+dependencies:
+  - name: postgresql
+    version: ~12.1.0
+    repository: https://charts.bitnami.com/bitnami
+    condition: postgresql.enabled
+  - name: redis
+    alias: cache
+    version: ">=17.0.0 <18.0.0"
+    repository: https://charts.bitnami.com/bitnami
+`)},
+		"v2/Chart.lock": {Data: []byte(`dependencies:
+- name: postgresql
+  repository: https://charts.bitnami.com/bitnami
+  version: 12.1.9
+- name: redis
+  repository: https://charts.bitnami.com/bitnami
+  version: 17.3.2
+digest: sha256:0000
+`)},
+		"v1/Chart.yaml":                {Data: []byte("apiVersion: v1\nname: legacy\n")},
+		"v1/requirements.yaml":         {Data: []byte("dependencies:\n  - name: nginx-ingress\n    version: 0.22.1\n    repository: \"@stable\"\n")},
+		"unlocked/Chart.yaml":          {Data: []byte("apiVersion: v2\nname: app\ndependencies:\n  - name: redis\n    version: 17.x\n")},
+		"invalid/Chart.yaml":           {Data: []byte("apiVersion: v2\nname: app\ndependencies:\n  - name: redis\n")},
+		"invalid/Chart.lock":           {Data: []byte("digest: sha256:0000\ndependencies: {\n")},
+		"invalid-v1/Chart.yaml":        {Data: []byte("apiVersion: v1\nname: legacy\n")},
+		"invalid-v1/requirements.yaml": {Data: []byte("dependencies: [\n")},
+	}
+
+	chart, _, err := ParseChartMetadata(fsys, "v2/Chart.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, []Dependency{
+		{
+			Name:            "postgresql",
+			Repository:      "https://charts.bitnami.com/bitnami",
+			Condition:       "postgresql.enabled",
+			Version:         "~12.1.0",
+			ResolvedVersion: "12.1.9",
+		},
+		{
+			Name:            "redis",
+			Repository:      "https://charts.bitnami.com/bitnami",
+			Alias:           "cache",
+			Version:         ">=17.0.0 <18.0.0",
+			ResolvedVersion: "17.3.2",
+		},
+	}, chart.Dependencies)
+
+	chart, _, err = ParseChartMetadata(fsys, "v1/Chart.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, []Dependency{{Name: "nginx-ingress", Repository: "@stable", Version: "0.22.1"}}, chart.Dependencies)
+
+	chart, _, err = ParseChartMetadata(fsys, "unlocked/Chart.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, []Dependency{{Name: "redis", Version: "17.x"}}, chart.Dependencies)
+
+	// Broken lock and requirements files are blamed on themselves, and the rest of the metadata is kept
+	chart, warnings, err := ParseChartMetadata(fsys, "invalid/Chart.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "app", chart.Name)
+	assert.Equal(t, []Dependency{{Name: "redis"}}, chart.Dependencies)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "invalid/Chart.lock", warnings[0].File)
+	assert.Equal(t, 2, warnings[0].Line)
+	assert.Equal(t, diagnostic.InvalidChartMetadataRule, warnings[0].Rule)
+
+	chart, warnings, err = ParseChartMetadata(fsys, "invalid-v1/Chart.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "legacy", chart.Name)
+	assert.Empty(t, chart.Dependencies)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "invalid-v1/requirements.yaml", warnings[0].File)
+}
+
+func TestDiagnosticFromError(t *testing.T) {
+	_, _, err := ParseValuesFS(fstest.MapFS{}, []string{"values.yaml"})
+	require.Error(t, err)
+	assert.Equal(t, diagnostic.UnreadableValuesFileRule, DiagnosticFromError(err, diagnostic.SeverityError).Rule)
+
+	assert.Equal(t, diagnostic.Diagnostic{
+		Rule:     diagnostic.ErrorRule,
+		Severity: diagnostic.SeverityError,
+		Message:  "template failed",
+	}, DiagnosticFromError(errors.New("template failed"), diagnostic.SeverityError))
+}
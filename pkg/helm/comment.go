@@ -1,5 +1,18 @@
 package helm
 
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+)
+
+var defaultCommentRegex = regexp.MustCompile("^\\s*#\\s*@default")
+var keyPathRegex = regexp.MustCompile(`^[\w\-./"\[\]]+$`)
+
 func ParseComment(commentLines []string) (string, ValueDescription) {
 	var valueKey string
 	var c ValueDescription
@@ -35,3 +48,155 @@ func ParseComment(commentLines []string) (string, ValueDescription) {
 
 	return valueKey, c
 }
+
+// IsKeyPath reports whether the key named by an old-style `# key -- description` comment can be a key path, rather
+// than text of a `# --` description which itself contains ` -- `
+func IsKeyPath(key string) bool {
+	return keyPathRegex.MatchString(key)
+}
+
+func hasDescriptionComment(commentLines []string) bool {
+	for _, line := range commentLines {
+		if match := valuesDescriptionRegex.FindStringSubmatch(line); len(match) > 2 && match[1] == "" && !defaultCommentRegex.MatchString(line) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func checkCommentLines(commentLines []string, file string, lineNumbers []int, column int) []diagnostic.Diagnostic {
+	warnings := make([]diagnostic.Diagnostic, 0)
+	foundDescription := false
+
+	for i, line := range commentLines {
+		lineNumber := lineNumbers[i]
+
+		if match := valuesDescriptionRegex.FindStringSubmatch(line); len(match) > 2 && !defaultCommentRegex.MatchString(line) {
+			// Comments naming their key are only picked up when they're not directly attached to a key, and otherwise
+			// ignored
+			if match[1] != "" && IsKeyPath(match[1]) {
+				warnings = append(warnings, diagnostic.Diagnostic{
+					File:     file,
+					Line:     lineNumber,
+					Column:   column,
+					Rule:     diagnostic.IgnoredKeyCommentRule,
+					Severity: diagnostic.SeverityWarning,
+					Message:  fmt.Sprintf("description comment for %s directly above a key is ignored, use `# --` instead", match[1]),
+				})
+				continue
+			}
+
+			foundDescription = foundDescription || match[1] == ""
+			continue
+		}
+
+		if !defaultCommentRegex.MatchString(line) {
+			continue
+		}
+
+		if !defaultValueRegex.MatchString(line) {
+			warnings = append(warnings, diagnostic.Diagnostic{
+				File:     file,
+				Line:     lineNumber,
+				Column:   column,
+				Rule:     diagnostic.MalformedDefaultRule,
+				Severity: diagnostic.SeverityWarning,
+				Message:  "malformed @default comment, expected `# @default -- <default>`",
+			})
+		} else if !foundDescription && hasDescriptionComment(commentLines[i+1:]) {
+			warnings = append(warnings, diagnostic.Diagnostic{
+				File:     file,
+				Line:     lineNumber,
+				Column:   column,
+				Rule:     diagnostic.DefaultBeforeDescriptionRule,
+				Severity: diagnostic.SeverityWarning,
+				Message:  "@default comment before the `# --` description is ignored, move it below the description",
+			})
+		} else if !foundDescription {
+			warnings = append(warnings, diagnostic.Diagnostic{
+				File:     file,
+				Line:     lineNumber,
+				Column:   column,
+				Rule:     diagnostic.DefaultWithoutDescriptionRule,
+				Severity: diagnostic.SeverityWarning,
+				Message:  "@default comment without a preceding `# --` description is ignored",
+			})
+		}
+	}
+
+	return warnings
+}
+
+// Head comments don't record their position, and blank lines within or below them aren't kept reliably. Each comment
+// line is found by searching the lines of the file upwards from the node, or assumed to sit directly above the node
+// when the lines of the file aren't known or don't match.
+func getCommentLineNumbers(commentLines []string, fileLines []string, nodeLine int) []int {
+	lineNumbers := make([]int, len(commentLines))
+	cursor := nodeLine - 2
+
+	for i := len(commentLines) - 1; i >= 0 && fileLines != nil; i-- {
+		comment := strings.TrimSpace(commentLines[i])
+		for cursor >= 0 && cursor < len(fileLines) && strings.TrimSpace(fileLines[cursor]) != comment {
+			cursor--
+		}
+
+		if cursor < 0 || cursor >= len(fileLines) {
+			fileLines = nil
+			break
+		}
+
+		lineNumbers[i] = cursor + 1
+		cursor--
+	}
+
+	if fileLines == nil {
+		for i := range commentLines {
+			lineNumbers[i] = nodeLine - len(commentLines) + i
+		}
+	}
+
+	return lineNumbers
+}
+
+func checkNodeComments(node *yaml.Node, file string, fileLines []string) []diagnostic.Diagnostic {
+	if node.HeadComment == "" {
+		return []diagnostic.Diagnostic{}
+	}
+
+	commentLines := strings.Split(node.HeadComment, "\n")
+	return checkCommentLines(commentLines, file, getCommentLineNumbers(commentLines, fileLines, node.Line), node.Column)
+}
+
+// CheckComments reports description comments in a parsed values file which are silently ignored when documenting it.
+// Without the contents of the file, comments are reported as if they were directly above their key.
+func CheckComments(node *yaml.Node, file string) []diagnostic.Diagnostic {
+	return checkComments(node, file, nil)
+}
+
+func getFileLines(contents []byte) []string {
+	return strings.Split(string(contents), "\n")
+}
+
+func checkComments(node *yaml.Node, file string, fileLines []string) []diagnostic.Diagnostic {
+	warnings := make([]diagnostic.Diagnostic, 0)
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			warnings = append(warnings, checkComments(child, file, fileLines)...)
+		}
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content); i += 2 {
+			warnings = append(warnings, checkNodeComments(node.Content[i], file, fileLines)...)
+			warnings = append(warnings, checkComments(node.Content[i+1], file, fileLines)...)
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			warnings = append(warnings, checkNodeComments(item, file, fileLines)...)
+			warnings = append(warnings, checkComments(item, file, fileLines)...)
+		}
+	}
+
+	return warnings
+}
@@ -0,0 +1,75 @@
+package helm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+)
+
+func TestCheckComments(t *testing.T) {
+	var values yaml.Node
+	err := yaml.Unmarshal([]byte(strings.TrimSpace(`
+# -- A valid description
+# @default -- one
+valid: 1
+controller:
+  # controller.replicas -- Number of replicas
+  replicas: 2
+  # -- The image
+  # @default: nginx
+  image: nginx
+list:
+  # @default -- two
+  - item
+	`)), &values)
+	require.NoError(t, err)
+
+	warnings := CheckComments(&values, "values.yaml")
+
+	assert.Equal(t, []diagnostic.Diagnostic{
+		{File: "values.yaml", Line: 5, Column: 3, Rule: diagnostic.IgnoredKeyCommentRule, Severity: diagnostic.SeverityWarning, Message: "description comment for controller.replicas directly above a key is ignored, use `# --` instead"},
+		{File: "values.yaml", Line: 8, Column: 3, Rule: diagnostic.MalformedDefaultRule, Severity: diagnostic.SeverityWarning, Message: "malformed @default comment, expected `# @default -- <default>`"},
+		{File: "values.yaml", Line: 11, Column: 5, Rule: diagnostic.DefaultWithoutDescriptionRule, Severity: diagnostic.SeverityWarning, Message: "@default comment without a preceding `# --` description is ignored"},
+	}, warnings)
+}
+
+func TestCheckCommentsDefaultBeforeDescription(t *testing.T) {
+	var values yaml.Node
+	err := yaml.Unmarshal([]byte(strings.TrimSpace(`
+# @default -- one
+# -- The description
+value: 1
+	`)), &values)
+	require.NoError(t, err)
+
+	assert.Equal(t, []diagnostic.Diagnostic{
+		{File: "values.yaml", Line: 1, Column: 1, Rule: diagnostic.DefaultBeforeDescriptionRule, Severity: diagnostic.SeverityWarning, Message: "@default comment before the `# --` description is ignored, move it below the description"},
+	}, CheckComments(&values, "values.yaml"))
+}
+
+func TestCheckCommentsBlankLines(t *testing.T) {
+	_, warnings, err := ParseValuesContents("values.yaml", []byte(`a:
+  # -- The image
+  # @default: nginx
+
+
+  image: nginx
+# -- Number of replicas
+
+# @default: two
+
+
+replicas: 2
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []diagnostic.Diagnostic{
+		{File: "values.yaml", Line: 3, Column: 3, Rule: diagnostic.MalformedDefaultRule, Severity: diagnostic.SeverityWarning, Message: "malformed @default comment, expected `# @default -- <default>`"},
+		{File: "values.yaml", Line: 9, Column: 1, Rule: diagnostic.MalformedDefaultRule, Severity: diagnostic.SeverityWarning, Message: "malformed @default comment, expected `# @default -- <default>`"},
+	}, warnings)
+}
@@ -0,0 +1,153 @@
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+)
+
+// Directory of a chart that its dependencies are unpacked or packaged into by helm dependency build
+const chartsDirectoryName = "charts"
+
+const valuesFileName = "values.yaml"
+
+// Subchart holds the values of one of the dependencies of a chart
+type Subchart struct {
+	Dependency Dependency
+
+	// Path of the values file, inside of the archive for packaged subcharts
+	Path   string
+	Values *yaml.Node
+}
+
+// Key returns the key that the parent chart sets the values of the subchart under, its alias or otherwise its name
+func (s Subchart) Key() string {
+	return s.Dependency.Key()
+}
+
+// Packaged charts contain a single directory named after the chart
+func readPackagedValuesFile(fsys fs.FS, archivePath string, chartName string) ([]byte, bool, error) {
+	archive, err := fs.ReadFile(fsys, archivePath)
+	if err != nil {
+		return nil, false, err
+	}
+
+	gzipReader, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer gzipReader.Close()
+
+	foundChart := false
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, foundChart, nil
+		}
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		switch path.Clean(header.Name) {
+		case path.Join(chartName, ChartFileName):
+			foundChart = true
+		case path.Join(chartName, valuesFileName):
+			contents, err := io.ReadAll(tarReader)
+			return contents, true, err
+		}
+	}
+}
+
+// The values of a dependency are read from its directory in charts/, from a packaged chart in charts/ or for file://
+// repositories from the directory they point to. Subcharts without a values file have no values, and the returned path
+// is empty if the subchart wasn't found at all.
+func readSubchartValuesFile(fsys fs.FS, chartPath string, dependency Dependency) (string, []byte, error) {
+	chartsPath := siblingPath(chartPath, chartsDirectoryName)
+	directories := []string{path.Join(chartsPath, dependency.Name)}
+
+	if strings.HasPrefix(dependency.Repository, "file://") {
+		directories = append(directories, siblingPath(chartPath, strings.TrimPrefix(dependency.Repository, "file://")))
+	}
+
+	for _, directory := range directories {
+		valuesPath := path.Join(directory, valuesFileName)
+		contents, err := fs.ReadFile(fsys, valuesPath)
+		if err == nil {
+			return valuesPath, contents, nil
+		}
+
+		if !errors.Is(err, fs.ErrNotExist) {
+			return valuesPath, nil, err
+		}
+
+		if _, err := fs.Stat(fsys, path.Join(directory, ChartFileName)); err == nil {
+			return valuesPath, nil, nil
+		}
+	}
+
+	// Globbing fails on filesystems that can't list directories, such as git revisions, where only unpacked subcharts
+	// are found
+	archives, _ := fs.Glob(fsys, path.Join(chartsPath, dependency.Name+"-*.tgz"))
+	for _, archivePath := range archives {
+		contents, found, err := readPackagedValuesFile(fsys, archivePath, dependency.Name)
+		valuesPath := path.Join(archivePath, dependency.Name, valuesFileName)
+
+		if err != nil {
+			return valuesPath, nil, err
+		}
+
+		if found {
+			return valuesPath, contents, nil
+		}
+	}
+
+	return "", nil, nil
+}
+
+// ParseSubcharts parses the values files of the dependencies of a chart. Dependencies that can't be found or parsed are
+// reported as warnings and left out.
+func ParseSubcharts(fsys fs.FS, chartPath string, chart ChartMetadata) ([]Subchart, []diagnostic.Diagnostic) {
+	subcharts := make([]Subchart, 0)
+	warnings := make([]diagnostic.Diagnostic, 0)
+
+	for _, dependency := range chart.Dependencies {
+		valuesPath, contents, err := readSubchartValuesFile(fsys, chartPath, dependency)
+		if err != nil {
+			warnings = append(warnings, DiagnosticFromError(&FileError{Path: valuesPath, Err: err}, diagnostic.SeverityWarning))
+			continue
+		}
+
+		if valuesPath == "" {
+			warnings = append(warnings, diagnostic.Diagnostic{
+				File:     chartPath,
+				Rule:     diagnostic.MissingSubchartRule,
+				Severity: diagnostic.SeverityWarning,
+				Message:  fmt.Sprintf("dependency %s not found in %s, run `helm dependency build` to document its values", dependency.Name, chartsDirectoryName),
+			})
+			continue
+		}
+
+		values, err := parseValuesContents(valuesPath, normalizeLineEndings(contents))
+		if err != nil {
+			warnings = append(warnings, DiagnosticFromError(err, diagnostic.SeverityWarning))
+			continue
+		}
+
+		subcharts = append(subcharts, Subchart{Dependency: dependency, Path: valuesPath, Values: &values})
+	}
+
+	return subcharts, warnings
+}
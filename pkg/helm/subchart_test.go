@@ -0,0 +1,75 @@
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+)
+
+func packageChart(t *testing.T, files map[string]string) []byte {
+	var archive bytes.Buffer
+	gzipWriter := gzip.NewWriter(&archive)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for name, contents := range files {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents))}))
+		_, err := tarWriter.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	return archive.Bytes()
+}
+
+func TestParseSubcharts(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app/charts/postgresql/values.yaml": {Data: []byte("auth:\n  username: app\n")},
+		"app/charts/common/Chart.yaml":      {Data: []byte("apiVersion: v2\nname: common\n")},
+		"app/charts/redis-17.3.2.tgz": {Data: packageChart(t, map[string]string{
+			"redis/Chart.yaml":                "apiVersion: v2\nname: redis\n",
+			"redis/values.yaml":               "replicas: 3\n",
+			"redis/charts/common/values.yaml": "nested: true\n",
+		})},
+		"app/charts/broken/values.yaml": {Data: []byte("a: [\n")},
+		"lib/values.yaml":               {Data: []byte("enabled: true\n")},
+	}
+
+	chart := ChartMetadata{Dependencies: []Dependency{
+		{Name: "postgresql"},
+		{Name: "common"},
+		{Name: "redis", Alias: "cache"},
+		{Name: "lib", Repository: "file://../lib"},
+		{Name: "broken"},
+		{Name: "missing"},
+	}}
+
+	subcharts, warnings := ParseSubcharts(fsys, "app/Chart.yaml", chart)
+
+	require.Len(t, subcharts, 4)
+	assert.Equal(t, "app/charts/postgresql/values.yaml", subcharts[0].Path)
+	assert.Equal(t, "postgresql", subcharts[0].Key())
+	assert.Equal(t, "auth", subcharts[0].Values.Content[0].Content[0].Value)
+
+	assert.Equal(t, "app/charts/common/values.yaml", subcharts[1].Path)
+	assert.Empty(t, subcharts[1].Values.Content)
+
+	assert.Equal(t, "app/charts/redis-17.3.2.tgz/redis/values.yaml", subcharts[2].Path)
+	assert.Equal(t, "cache", subcharts[2].Key())
+	assert.Equal(t, "replicas", subcharts[2].Values.Content[0].Content[0].Value)
+
+	assert.Equal(t, "lib/values.yaml", subcharts[3].Path)
+
+	require.Len(t, warnings, 2)
+	assert.Equal(t, diagnostic.InvalidYamlRule, warnings[0].Rule)
+	assert.Equal(t, "app/charts/broken/values.yaml", warnings[0].File)
+	assert.Equal(t, diagnostic.MissingSubchartRule, warnings[1].Rule)
+	assert.Equal(t, "app/Chart.yaml", warnings[1].File)
+}
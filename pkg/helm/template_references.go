@@ -0,0 +1,321 @@
+package helm
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template/parse"
+
+	"github.com/Masterminds/sprig"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+)
+
+// Directory of a chart holding the templates that use its values
+const templatesDirectoryName = "templates"
+
+// Functions helm adds on top of sprig, and those built into text/template. Templates are only parsed, so the functions
+// merely need to exist.
+var helmTemplateFunctions = []string{
+	"include", "tpl", "required", "lookup",
+	"toYaml", "mustToYaml", "toYamlPretty", "fromYaml", "fromYamlArray",
+	"toJson", "mustToJson", "fromJson", "fromJsonArray", "toToml", "fromToml",
+	"and", "or", "not", "call", "html", "index", "slice", "js", "len", "print", "printf", "println", "urlquery",
+	"eq", "ne", "lt", "le", "gt", "ge",
+}
+
+// ValuesReference is a use of a value in one of the templates of a chart
+type ValuesReference struct {
+	File   string
+	Line   int
+	Column int
+
+	// Keys leading to the value from the root of the values, empty if the template uses all values at once
+	Path []string
+}
+
+func getTemplateFunctions() map[string]interface{} {
+	functions := make(map[string]interface{})
+	for name, function := range sprig.TxtFuncMap() {
+		functions[name] = function
+	}
+
+	for _, name := range helmTemplateFunctions {
+		functions[name] = func() {}
+	}
+
+	return functions
+}
+
+// What the dot, a variable or an expression evaluates to, as far as it can be told without rendering the template:
+// the top-level object templates are executed with, a value within .Values or something else
+type templateScope struct {
+	root   bool
+	values bool
+	path   []string
+}
+
+func valuesScope(path []string) templateScope {
+	return templateScope{values: true, path: append([]string{}, path...)}
+}
+
+type referenceFinder struct {
+	file       string
+	text       string
+	references []ValuesReference
+	variables  map[string]templateScope
+}
+
+func (f *referenceFinder) addReference(position parse.Pos, path []string) {
+	line := 1 + strings.Count(f.text[:position], "\n")
+	column := int(position) - strings.LastIndex(f.text[:position], "\n")
+
+	f.references = append(f.references, ValuesReference{File: f.file, Line: line, Column: column, Path: path})
+}
+
+// The position of fields and variables is that of their last identifier rather than of their start
+func (f *referenceFinder) nodeStart(node parse.Node) parse.Pos {
+	text := node.String()
+	end := int(node.Position()) + len(text)
+	if end > len(f.text) {
+		end = len(f.text)
+	}
+
+	if start := strings.LastIndex(f.text[:end], text); start >= 0 {
+		return parse.Pos(start)
+	}
+
+	return node.Position()
+}
+
+func fieldScope(base templateScope, fields []string) templateScope {
+	if base.values {
+		return valuesScope(append(append([]string{}, base.path...), fields...))
+	}
+
+	if base.root && len(fields) > 0 && fields[0] == "Values" {
+		return valuesScope(fields[1:])
+	}
+
+	if base.root && len(fields) == 0 {
+		return base
+	}
+
+	return templateScope{}
+}
+
+func isIndexCommand(command *parse.CommandNode) bool {
+	identifier, ok := command.Args[0].(*parse.IdentifierNode)
+	return ok && identifier.Ident == "index" && len(command.Args) > 1
+}
+
+// index with constant string keys is resolved, indexing stops at the first key only known when rendering
+func (f *referenceFinder) indexScope(command *parse.CommandNode, dot templateScope) templateScope {
+	scope := f.nodeScope(command.Args[1], dot)
+	if !scope.values {
+		return scope
+	}
+
+	for _, arg := range command.Args[2:] {
+		key, ok := arg.(*parse.StringNode)
+		if !ok {
+			break
+		}
+
+		scope.path = append(scope.path, key.Text)
+	}
+
+	return scope
+}
+
+func (f *referenceFinder) commandScope(command *parse.CommandNode, dot templateScope) templateScope {
+	if isIndexCommand(command) {
+		return f.indexScope(command, dot)
+	}
+
+	if len(command.Args) == 1 {
+		return f.nodeScope(command.Args[0], dot)
+	}
+
+	return templateScope{}
+}
+
+func (f *referenceFinder) nodeScope(node parse.Node, dot templateScope) templateScope {
+	switch node := node.(type) {
+	case *parse.DotNode:
+		return dot
+	case *parse.FieldNode:
+		return fieldScope(dot, node.Ident)
+	case *parse.VariableNode:
+		if node.Ident[0] == "$" {
+			return fieldScope(templateScope{root: true}, node.Ident[1:])
+		}
+
+		return fieldScope(f.variables[node.Ident[0]], node.Ident[1:])
+	case *parse.ChainNode:
+		return fieldScope(f.nodeScope(node.Node, dot), node.Field)
+	case *parse.PipeNode:
+		if len(node.Cmds) == 0 {
+			return templateScope{}
+		}
+
+		// The value of a pipeline is the value of its last command
+		return f.commandScope(node.Cmds[len(node.Cmds)-1], dot)
+	}
+
+	return templateScope{}
+}
+
+func (f *referenceFinder) findInCommand(command *parse.CommandNode, dot templateScope) {
+	args := command.Args
+	if isIndexCommand(command) {
+		if scope := f.indexScope(command, dot); scope.values {
+			f.addReference(command.Position(), scope.path)
+		}
+
+		args = args[2:]
+	}
+
+	for _, arg := range args {
+		switch arg := arg.(type) {
+		case *parse.PipeNode:
+			f.findInPipe(arg, dot)
+		case *parse.DotNode, *parse.FieldNode, *parse.VariableNode, *parse.ChainNode:
+			if scope := f.nodeScope(arg, dot); scope.values {
+				f.addReference(f.nodeStart(arg), scope.path)
+			}
+		}
+	}
+}
+
+func (f *referenceFinder) findInPipe(pipe *parse.PipeNode, dot templateScope) {
+	for _, command := range pipe.Cmds {
+		f.findInCommand(command, dot)
+	}
+}
+
+func (f *referenceFinder) declare(pipe *parse.PipeNode, scope templateScope) {
+	for _, variable := range pipe.Decl {
+		f.variables[variable.Ident[0]] = scope
+	}
+}
+
+func (f *referenceFinder) findInNode(node parse.Node, dot templateScope) {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return
+		}
+
+		for _, child := range node.Nodes {
+			f.findInNode(child, dot)
+		}
+	case *parse.ActionNode:
+		f.findInPipe(node.Pipe, dot)
+		f.declare(node.Pipe, f.nodeScope(node.Pipe, dot))
+	case *parse.IfNode:
+		f.findInPipe(node.Pipe, dot)
+		f.declare(node.Pipe, f.nodeScope(node.Pipe, dot))
+		f.findInNode(node.List, dot)
+		f.findInNode(node.ElseList, dot)
+	case *parse.WithNode:
+		f.findInPipe(node.Pipe, dot)
+		scope := f.nodeScope(node.Pipe, dot)
+		f.declare(node.Pipe, scope)
+		f.findInNode(node.List, scope)
+		f.findInNode(node.ElseList, dot)
+	case *parse.RangeNode:
+		// The elements of a list or object aren't known without rendering
+		f.findInPipe(node.Pipe, dot)
+		f.declare(node.Pipe, templateScope{})
+		f.findInNode(node.List, templateScope{})
+		f.findInNode(node.ElseList, dot)
+	case *parse.TemplateNode:
+		if node.Pipe != nil {
+			f.findInPipe(node.Pipe, dot)
+		}
+	}
+}
+
+// Named templates are assumed to be included with the top-level object as their dot, as is customary in charts
+func findTemplateReferences(file string, text string, functions map[string]interface{}) ([]ValuesReference, error) {
+	trees, err := parse.Parse(file, text, "", "", functions)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(trees))
+	for name := range trees {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	finder := &referenceFinder{file: file, text: text, references: make([]ValuesReference, 0)}
+	for _, name := range names {
+		finder.variables = make(map[string]templateScope)
+		finder.findInNode(trees[name].Root, templateScope{root: true})
+	}
+
+	sort.SliceStable(finder.references, func(i, j int) bool {
+		return finder.references[i].Line < finder.references[j].Line
+	})
+
+	return finder.references, nil
+}
+
+// Parse errors name the template and line before the message, as in `template: <file>:<line>: <message>`
+func templateErrorLine(file string, err error) int {
+	location := strings.TrimPrefix(err.Error(), fmt.Sprintf("template: %s:", file))
+	line, _ := strconv.Atoi(strings.SplitN(location, ":", 2)[0])
+
+	return line
+}
+
+// FindValuesReferences returns the uses of values in the templates of the chart next to a values file, and whether the
+// chart has a templates directory at all. Templates that can't be parsed are skipped and reported as warnings.
+func FindValuesReferences(fsys fs.FS, valuesPath string) ([]ValuesReference, []diagnostic.Diagnostic, bool, error) {
+	templatesPath := siblingPath(valuesPath, templatesDirectoryName)
+	if info, err := fs.Stat(fsys, templatesPath); err != nil || !info.IsDir() {
+		return nil, nil, false, nil
+	}
+
+	references := make([]ValuesReference, 0)
+	warnings := make([]diagnostic.Diagnostic, 0)
+	functions := getTemplateFunctions()
+
+	err := fs.WalkDir(fsys, templatesPath, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		contents, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+
+		fileReferences, err := findTemplateReferences(file, string(normalizeLineEndings(contents)), functions)
+		if err != nil {
+			warnings = append(warnings, diagnostic.Diagnostic{
+				File:     file,
+				Line:     templateErrorLine(file, err),
+				Rule:     diagnostic.InvalidChartTemplateRule,
+				Severity: diagnostic.SeverityWarning,
+				Message:  fmt.Sprintf("failed to parse template, its uses of values aren't checked: %s", err),
+			})
+			return nil
+		}
+
+		references = append(references, fileReferences...)
+		return nil
+	})
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, false, nil
+	}
+
+	return references, warnings, true, err
+}
@@ -0,0 +1,85 @@
+package helm
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+)
+
+const deploymentTemplate = `{{- define "chart.labels" -}}
+app: {{ .Values.nameOverride | default .Chart.Name }}
+{{- end }}
+replicas: {{ .Values.replicaCount }}
+image: {{ index .Values "image" "repository" }}:{{ $.Values.image.tag }}
+{{- with .Values.resources }}
+resources: {{ toYaml .limits }}
+{{- end }}
+{{- $service := .Values.service }}
+port: {{ $service.port }}
+{{- range .Values.extraEnv }}
+- {{ .name }}
+{{- end }}
+{{ index .Values.podAnnotations $.Release.Name }}
+`
+
+func referencePaths(references []ValuesReference) [][]string {
+	paths := make([][]string, 0, len(references))
+	for _, reference := range references {
+		paths = append(paths, reference.Path)
+	}
+
+	return paths
+}
+
+func TestFindValuesReferences(t *testing.T) {
+	fsys := fstest.MapFS{
+		"chart/values.yaml":                 {Data: []byte("replicaCount: 1\n")},
+		"chart/templates/deployment.yaml":   {Data: []byte(deploymentTemplate)},
+		"chart/templates/configmap.yaml":    {Data: []byte("data: {{ toYaml .Values | quote }}\n")},
+		"chart/templates/broken.yaml":       {Data: []byte("broken: true\n{{ .Values.broken ) }}\n")},
+		"chart/templates/tests/secret.yaml": {Data: []byte("{{ tpl .Values.secret . }}\n")},
+	}
+
+	references, warnings, found, err := FindValuesReferences(fsys, "chart/values.yaml")
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "chart/templates/broken.yaml", warnings[0].File)
+	assert.Equal(t, 2, warnings[0].Line)
+	assert.Equal(t, diagnostic.InvalidChartTemplateRule, warnings[0].Rule)
+
+	assert.Equal(t, [][]string{
+		{},
+		{"nameOverride"},
+		{"replicaCount"},
+		{"image", "repository"},
+		{"image", "tag"},
+		{"resources"},
+		{"resources", "limits"},
+		{"service"},
+		{"service", "port"},
+		{"extraEnv"},
+		{"podAnnotations"},
+		{"secret"},
+	}, referencePaths(references))
+
+	assert.Equal(t, ValuesReference{File: "chart/templates/deployment.yaml", Line: 5, Column: 11, Path: []string{"image", "repository"}}, references[3])
+	assert.Equal(t, ValuesReference{File: "chart/templates/deployment.yaml", Line: 5, Column: 52, Path: []string{"image", "tag"}}, references[4])
+}
+
+func TestFindValuesReferencesWithoutTemplates(t *testing.T) {
+	fsys := fstest.MapFS{
+		"values.yaml": {Data: []byte("replicaCount: 1\n")},
+	}
+
+	references, warnings, found, err := FindValuesReferences(fsys, "values.yaml")
+	require.NoError(t, err)
+	assert.False(t, found)
+	assert.Empty(t, references)
+	assert.Empty(t, warnings)
+}
@@ -0,0 +1,337 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+	"github.com/theEndBeta/yaml-docs/pkg/document"
+	"github.com/theEndBeta/yaml-docs/pkg/helm"
+)
+
+var commentRegex = regexp.MustCompile(`^\s*#`)
+var descriptionCommentRegex = regexp.MustCompile(`^\s*#\s*--(\s|$)`)
+
+// Rules that are checked unless disabled, rules not listed here are only checked when enabled
+var defaultRules = []string{
+	diagnostic.UnreadableValuesFileRule,
+	diagnostic.InvalidYamlRule,
+	diagnostic.IgnoredKeyCommentRule,
+	diagnostic.MalformedDefaultRule,
+	diagnostic.DefaultWithoutDescriptionRule,
+	diagnostic.DefaultBeforeDescriptionRule,
+	diagnostic.UnknownKeyCommentRule,
+	diagnostic.DetachedDescriptionRule,
+	diagnostic.DuplicateKeyRule,
+	diagnostic.InvalidChartTemplateRule,
+}
+
+// The templates of a chart are only parsed when one of templateRules is enabled
+var optionalRules = []string{
+	diagnostic.UndocumentedKeyRule,
+	diagnostic.DescriptionStyleRule,
+	diagnostic.UnusedValueRule,
+	diagnostic.UndefinedValueRule,
+}
+
+var templateRules = []string{diagnostic.UnusedValueRule, diagnostic.UndefinedValueRule}
+
+// Rules returns the ids of all rules that can be enabled or disabled
+func Rules() []string {
+	rules := append(append([]string{}, defaultRules...), optionalRules...)
+	sort.Strings(rules)
+
+	return rules
+}
+
+// Config selects the rules that are checked, on top of the rules checked by default
+type Config struct {
+	Enable  []string
+	Disable []string
+}
+
+// Validate returns an error for rules in the config which don't exist
+func (c Config) Validate() error {
+	known := make(map[string]bool)
+	for _, rule := range Rules() {
+		known[rule] = true
+	}
+
+	for _, rule := range append(append([]string{}, c.Enable...), c.Disable...) {
+		if !known[rule] {
+			return fmt.Errorf("unknown lint rule `%s`, must be one of (%s)", rule, strings.Join(Rules(), ", "))
+		}
+	}
+
+	return nil
+}
+
+// Enabled reports whether a rule is checked, disabling a rule takes precedence over enabling it
+func (c Config) Enabled(rule string) bool {
+	for _, disabled := range c.Disable {
+		if disabled == rule {
+			return false
+		}
+	}
+
+	for _, enabled := range append(append([]string{}, defaultRules...), c.Enable...) {
+		if enabled == rule {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c Config) anyEnabled(rules []string) bool {
+	for _, rule := range rules {
+		if c.Enabled(rule) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Lint checks the contents of a values file, and the templates of the chart it belongs to, against the rules enabled in
+// config. Findings are sorted by file and line, those in the values file first.
+func Lint(file string, contents []byte, config Config, options document.Options) []diagnostic.Diagnostic {
+	findings := make([]diagnostic.Diagnostic, 0)
+
+	valuesData, warnings, err := helm.ParseValuesContents(file, contents)
+	if err != nil {
+		findings = append(findings, helm.DiagnosticFromError(err, diagnostic.SeverityError))
+		return filterFindings(findings, file, config)
+	}
+
+	lines := strings.Split(strings.Replace(string(contents), "\r\n", "\n", -1), "\n")
+
+	findings = append(findings, warnings...)
+	findings = append(findings, checkDuplicateKeys(valuesData, file)...)
+	findings = append(findings, checkDetachedDescriptions(lines, file)...)
+	findings = append(findings, checkKeyComments(valuesData, lines, document.KeyPaths(valuesData), file)...)
+
+	rows, err := document.ValueRows(valuesData, options)
+	if err != nil {
+		findings = append(findings, diagnostic.Diagnostic{File: file, Rule: diagnostic.ErrorRule, Severity: diagnostic.SeverityError, Message: err.Error()})
+	} else {
+		findings = append(findings, checkDescriptionStyle(rows, file)...)
+		findings = append(findings, document.UndocumentedKeys(rows, file)...)
+		if config.anyEnabled(templateRules) {
+			findings = append(findings, checkTemplateUsage(valuesData, rows, file, options)...)
+		}
+	}
+
+	return filterFindings(findings, file, config)
+}
+
+func filterFindings(findings []diagnostic.Diagnostic, file string, config Config) []diagnostic.Diagnostic {
+	enabled := make([]diagnostic.Diagnostic, 0, len(findings))
+
+	// Errors other rules don't cover can't be disabled
+	for _, finding := range findings {
+		if finding.Rule == diagnostic.ErrorRule || config.Enabled(finding.Rule) {
+			enabled = append(enabled, finding)
+		}
+	}
+
+	// Findings in templates are reported after those in the values file
+	sort.SliceStable(enabled, func(i, j int) bool {
+		if enabled[i].File != enabled[j].File {
+			return enabled[i].File == file || enabled[j].File != file && enabled[i].File < enabled[j].File
+		}
+
+		return enabled[i].Line < enabled[j].Line
+	})
+
+	return enabled
+}
+
+func checkDuplicateKeys(node *yaml.Node, file string) []diagnostic.Diagnostic {
+	findings := make([]diagnostic.Diagnostic, 0)
+
+	if node.Kind == yaml.MappingNode {
+		firstLines := make(map[string]int)
+
+		for i := 0; i < len(node.Content); i += 2 {
+			key := node.Content[i]
+
+			if firstLine, ok := firstLines[key.Value]; ok {
+				findings = append(findings, diagnostic.Diagnostic{
+					File:     file,
+					Line:     key.Line,
+					Column:   key.Column,
+					Rule:     diagnostic.DuplicateKeyRule,
+					Severity: diagnostic.SeverityWarning,
+					Message:  fmt.Sprintf("key %s is already defined on line %d", key.Value, firstLine),
+				})
+				continue
+			}
+
+			firstLines[key.Value] = key.Line
+		}
+	}
+
+	for _, child := range node.Content {
+		findings = append(findings, checkDuplicateKeys(child, file)...)
+	}
+
+	return findings
+}
+
+// Descriptions only belong to a key when the comment is directly above it, a blank line in between detaches them
+func checkDetachedDescriptions(lines []string, file string) []diagnostic.Diagnostic {
+	findings := make([]diagnostic.Diagnostic, 0)
+	descriptionLine := 0
+
+	for i, line := range lines {
+		if commentRegex.MatchString(line) {
+			if descriptionLine == 0 && descriptionCommentRegex.MatchString(line) {
+				descriptionLine = i + 1
+			}
+
+			continue
+		}
+
+		if descriptionLine != 0 && strings.TrimSpace(line) == "" && i < len(lines)-1 {
+			findings = append(findings, diagnostic.Diagnostic{
+				File:     file,
+				Line:     descriptionLine,
+				Column:   strings.Index(lines[descriptionLine-1], "#") + 1,
+				Rule:     diagnostic.DetachedDescriptionRule,
+				Severity: diagnostic.SeverityWarning,
+				Message:  "`# --` description is separated from the key below it by a blank line and is ignored",
+			})
+		}
+
+		descriptionLine = 0
+	}
+
+	return findings
+}
+
+type commentLine struct {
+	text   string
+	line   int
+	column int
+}
+
+// Searches the lines of the file from index start in direction step for a comment line, returns -1 when not found
+func findCommentLine(lines []string, comment string, start int, step int) int {
+	for i := start; i >= 0 && i < len(lines); i += step {
+		if strings.TrimSpace(lines[i]) == comment {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Comments of the parsed values, located in the lines of the file. Head comments are searched upwards from their node
+// and foot comments downwards, so lines of block scalars which look like comments are never picked up.
+func getCommentLines(node *yaml.Node, lines []string) []commentLine {
+	comments := make([]commentLine, 0)
+
+	if node.HeadComment != "" {
+		headLines := strings.Split(node.HeadComment, "\n")
+		cursor := node.Line - 2
+		located := make([]commentLine, 0, len(headLines))
+
+		for i := len(headLines) - 1; i >= 0 && cursor >= 0; i-- {
+			if cursor = findCommentLine(lines, strings.TrimSpace(headLines[i]), cursor, -1); cursor >= 0 {
+				located = append([]commentLine{{text: lines[cursor], line: cursor + 1, column: strings.Index(lines[cursor], "#") + 1}}, located...)
+				cursor--
+			}
+		}
+
+		comments = append(comments, located...)
+	}
+
+	if node.LineComment != "" && node.Line > 0 && node.Line <= len(lines) {
+		line := lines[node.Line-1]
+		if column := strings.LastIndex(line, node.LineComment); column >= 0 {
+			comments = append(comments, commentLine{text: line[column:], line: node.Line, column: column + 1})
+		}
+	}
+
+	for _, child := range node.Content {
+		comments = append(comments, getCommentLines(child, lines)...)
+	}
+
+	if node.FootComment != "" {
+		cursor := node.Line
+		for _, footLine := range strings.Split(node.FootComment, "\n") {
+			if cursor = findCommentLine(lines, strings.TrimSpace(footLine), cursor, 1); cursor < 0 {
+				break
+			}
+
+			comments = append(comments, commentLine{text: lines[cursor], line: cursor + 1, column: strings.Index(lines[cursor], "#") + 1})
+			cursor++
+		}
+	}
+
+	return comments
+}
+
+// Old-style comments name the key they describe, which may no longer exist after the values were restructured
+func checkKeyComments(node *yaml.Node, lines []string, keys map[string]bool, file string) []diagnostic.Diagnostic {
+	findings := make([]diagnostic.Diagnostic, 0)
+	reported := make(map[int]bool)
+
+	for _, comment := range getCommentLines(node, lines) {
+		key, _ := helm.ParseComment([]string{comment.text})
+		if key == "" || strings.HasPrefix(key, "@") || !helm.IsKeyPath(key) || keys[key] || reported[comment.line] {
+			continue
+		}
+
+		reported[comment.line] = true
+		findings = append(findings, diagnostic.Diagnostic{
+			File:     file,
+			Line:     comment.line,
+			Column:   comment.column,
+			Rule:     diagnostic.UnknownKeyCommentRule,
+			Severity: diagnostic.SeverityWarning,
+			Message:  fmt.Sprintf("description comment refers to %s, which doesn't exist", key),
+		})
+	}
+
+	return findings
+}
+
+func checkDescriptionStyle(rows []document.ValueRow, file string) []diagnostic.Diagnostic {
+	findings := make([]diagnostic.Diagnostic, 0)
+
+	for _, row := range rows {
+		description := []rune(strings.TrimSpace(row.Description))
+		if len(description) == 0 {
+			continue
+		}
+
+		problem := ""
+		if unicode.IsLower(description[0]) {
+			problem = "start with a capital letter"
+		} else if !strings.ContainsRune(".!?", description[len(description)-1]) {
+			problem = "end with punctuation"
+		}
+
+		if problem == "" {
+			continue
+		}
+
+		findings = append(findings, diagnostic.Diagnostic{
+			File:     file,
+			Line:     row.LineNumber,
+			Column:   row.Column,
+			Rule:     diagnostic.DescriptionStyleRule,
+			Severity: diagnostic.SeverityWarning,
+			Message:  fmt.Sprintf("description of %s should %s", row.Key, problem),
+		})
+	}
+
+	return findings
+}
@@ -0,0 +1,165 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+	"github.com/theEndBeta/yaml-docs/pkg/document"
+)
+
+const lintValues = `# -- Number of replicas.
+replicas: 1
+# controller.image -- The controller image.
+
+# -- Detached description.
+
+image:
+  # @default -- latest
+  # -- Image tag.
+  tag: ""
+  # -- the pull policy
+  pullPolicy: IfNotPresent
+replicas: 2
+service:
+  port: 80
+`
+
+func lintRules(findings []diagnostic.Diagnostic) []string {
+	rules := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		rules = append(rules, finding.Rule)
+	}
+
+	return rules
+}
+
+func TestLint(t *testing.T) {
+	findings := Lint("values.yaml", []byte(lintValues), Config{}, document.Options{})
+
+	assert.Equal(t, []diagnostic.Diagnostic{
+		{File: "values.yaml", Line: 3, Column: 1, Rule: diagnostic.UnknownKeyCommentRule, Severity: diagnostic.SeverityWarning, Message: "description comment refers to controller.image, which doesn't exist"},
+		{File: "values.yaml", Line: 5, Column: 1, Rule: diagnostic.DetachedDescriptionRule, Severity: diagnostic.SeverityWarning, Message: "`# --` description is separated from the key below it by a blank line and is ignored"},
+		{File: "values.yaml", Line: 8, Column: 3, Rule: diagnostic.DefaultBeforeDescriptionRule, Severity: diagnostic.SeverityWarning, Message: "@default comment before the `# --` description is ignored, move it below the description"},
+		{File: "values.yaml", Line: 13, Column: 1, Rule: diagnostic.DuplicateKeyRule, Severity: diagnostic.SeverityWarning, Message: "key replicas is already defined on line 2"},
+	}, findings)
+}
+
+func TestLintEnableAndDisable(t *testing.T) {
+	config := Config{
+		Enable:  []string{diagnostic.UndocumentedKeyRule, diagnostic.DescriptionStyleRule},
+		Disable: []string{diagnostic.DescriptionStyleRule, diagnostic.DuplicateKeyRule},
+	}
+
+	findings := Lint("values.yaml", []byte(lintValues), config, document.Options{})
+
+	assert.Equal(t, []string{
+		diagnostic.UnknownKeyCommentRule,
+		diagnostic.DetachedDescriptionRule,
+		diagnostic.DefaultBeforeDescriptionRule,
+		diagnostic.UndocumentedKeyRule,
+		diagnostic.UndocumentedKeyRule,
+	}, lintRules(findings))
+	assert.Equal(t, 13, findings[3].Line)
+	assert.Equal(t, 15, findings[4].Line)
+}
+
+func TestLintInvalidYaml(t *testing.T) {
+	findings := Lint("values.yaml", []byte("a: [\n"), Config{}, document.Options{})
+
+	require.Len(t, findings, 1)
+	assert.Equal(t, diagnostic.InvalidYamlRule, findings[0].Rule)
+	assert.Equal(t, diagnostic.SeverityError, findings[0].Severity)
+}
+
+func TestLintDescriptionStyle(t *testing.T) {
+	findings := Lint("values.yaml", []byte(lintValues), Config{Enable: []string{diagnostic.DescriptionStyleRule}}, document.Options{})
+
+	require.Len(t, findings, 5)
+	assert.Equal(t, "description of image.pullPolicy should start with a capital letter", findings[3].Message)
+}
+
+func TestLintDescriptionPunctuation(t *testing.T) {
+	config := Config{Enable: []string{diagnostic.DescriptionStyleRule}}
+	findings := Lint("values.yaml", []byte("# -- Number of replicas\nreplicas: 1\n# -- `true` to enable!\nenabled: true\n"), config, document.Options{})
+
+	require.Len(t, findings, 1)
+	assert.Equal(t, "description of replicas should end with punctuation", findings[0].Message)
+}
+
+func TestLintKeyComments(t *testing.T) {
+	const values = `# first.key -- Refers to a missing key.
+
+# -- Use foo -- not bar.
+foo: 1
+script: |
+  # gone.key -- Not a comment.
+  echo
+# -- Image.
+image: nginx # image.tag -- Missing as well.
+# last.key -- Missing at the end.
+`
+
+	findings := Lint("values.yaml", []byte(values), Config{}, document.Options{})
+
+	assert.Equal(t, []diagnostic.Diagnostic{
+		{File: "values.yaml", Line: 1, Column: 1, Rule: diagnostic.UnknownKeyCommentRule, Severity: diagnostic.SeverityWarning, Message: "description comment refers to first.key, which doesn't exist"},
+		{File: "values.yaml", Line: 9, Column: 14, Rule: diagnostic.UnknownKeyCommentRule, Severity: diagnostic.SeverityWarning, Message: "description comment refers to image.tag, which doesn't exist"},
+		{File: "values.yaml", Line: 10, Column: 1, Rule: diagnostic.UnknownKeyCommentRule, Severity: diagnostic.SeverityWarning, Message: "description comment refers to last.key, which doesn't exist"},
+	}, findings)
+}
+
+const templateValues = `# -- Number of replicas.
+replicas: 1
+image:
+  # -- Image tag.
+  tag: latest
+  # -- Image pull policy.
+  pullPolicy: IfNotPresent
+# -- Resources of the pod.
+resources: {}
+# -- Values of the redis dependency.
+redis:
+  enabled: true
+`
+
+const templateDeployment = `replicas: {{ .Values.replicas }}
+image: {{ .Values.image.tag }}
+{{- with .Values.resources }}
+resources: {{ toYaml .limits }}
+{{- end }}
+affinity: {{ toYaml .Values.affinity }}
+`
+
+func TestLintTemplateUsage(t *testing.T) {
+	fsys := fstest.MapFS{
+		"chart/Chart.yaml":                {Data: []byte("apiVersion: v2\nname: chart\ndependencies:\n  - name: redis\n")},
+		"chart/values.yaml":               {Data: []byte(templateValues)},
+		"chart/values-prod.yaml":          {Data: []byte("# -- Replicas in production.\nreplicas: 3\n")},
+		"chart/templates/deployment.yaml": {Data: []byte(templateDeployment)},
+	}
+
+	assert.Empty(t, Lint("chart/values.yaml", []byte(templateValues), Config{}, document.Options{FS: fsys}))
+
+	config := Config{Enable: []string{diagnostic.UnusedValueRule, diagnostic.UndefinedValueRule}}
+	findings := Lint("chart/values.yaml", []byte(templateValues), config, document.Options{FS: fsys})
+
+	assert.Equal(t, []diagnostic.Diagnostic{
+		{File: "chart/values.yaml", Line: 7, Column: 3, Rule: diagnostic.UnusedValueRule, Severity: diagnostic.SeverityWarning, Message: "image.pullPolicy is documented but not used by any template of the chart"},
+		{File: "chart/templates/deployment.yaml", Line: 6, Column: 21, Rule: diagnostic.UndefinedValueRule, Severity: diagnostic.SeverityWarning, Message: ".Values.affinity is used but not defined in chart/values.yaml"},
+	}, findings)
+
+	assert.Empty(t, Lint("chart/values-prod.yaml", []byte("# -- Replicas in production.\nreplicas: 3\n"), config, document.Options{FS: fsys}))
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, Config{Enable: []string{diagnostic.UndocumentedKeyRule}}.Validate())
+
+	err := Config{Disable: []string{"no-such-rule"}}.Validate()
+	require.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "unknown lint rule `no-such-rule`"))
+}
@@ -0,0 +1,143 @@
+package lint
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+	"github.com/theEndBeta/yaml-docs/pkg/document"
+	"github.com/theEndBeta/yaml-docs/pkg/helm"
+)
+
+const chartValuesFileName = "values.yaml"
+
+// Whether a key is the same as, or within the object or list of, another key
+func isWithinKey(key string, parent string) bool {
+	return key == parent || strings.HasPrefix(key, parent+".") || strings.HasPrefix(key, parent+"[")
+}
+
+// Values under global and under the keys of dependencies are passed on to subcharts, which use them in their own
+// templates
+func getSubchartKeys(options document.Options, file string) []string {
+	keys := []string{"global"}
+
+	chart, _, err := helm.ParseChartMetadata(options.FileSystem(), helm.ChartFilePath(file))
+	if err != nil {
+		return keys
+	}
+
+	for _, dependency := range chart.Dependencies {
+		keys = append(keys, document.FormatKey([]string{dependency.Key()}))
+	}
+
+	return keys
+}
+
+func checkUnusedValues(rows []document.ValueRow, references []helm.ValuesReference, subchartKeys []string, file string) []diagnostic.Diagnostic {
+	findings := make([]diagnostic.Diagnostic, 0)
+	usedKeys := make([]string, 0, len(references))
+
+	for _, reference := range references {
+		// Templates passing all values on at once may use any of them
+		if len(reference.Path) == 0 {
+			return findings
+		}
+
+		usedKeys = append(usedKeys, document.FormatKey(reference.Path))
+	}
+
+rows:
+	for _, row := range rows {
+		for _, key := range append(usedKeys, subchartKeys...) {
+			// A template using an object uses everything in it, and a documented object is used when any of it is
+			if isWithinKey(row.Key, key) || isWithinKey(key, row.Key) {
+				continue rows
+			}
+		}
+
+		findings = append(findings, diagnostic.Diagnostic{
+			File:     file,
+			Line:     row.LineNumber,
+			Column:   row.Column,
+			Rule:     diagnostic.UnusedValueRule,
+			Severity: diagnostic.SeverityWarning,
+			Message:  fmt.Sprintf("%s is documented but not used by any template of the chart", row.Key),
+		})
+	}
+
+	return findings
+}
+
+// Objects and lists without any keys in the values file, like `resources: {}`, are left for users to fill in and any
+// value under them is considered defined
+func isDefined(keys map[string]bool, path []string) bool {
+	for i := len(path); i > 0; i-- {
+		key := document.FormatKey(path[:i])
+		if !keys[key] {
+			continue
+		}
+
+		if i == len(path) {
+			return true
+		}
+
+		for other := range keys {
+			if other != key && isWithinKey(other, key) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func checkUndefinedValues(keys map[string]bool, references []helm.ValuesReference, file string) []diagnostic.Diagnostic {
+	findings := make([]diagnostic.Diagnostic, 0)
+	reported := make(map[string]bool)
+
+	for _, reference := range references {
+		key := document.FormatKey(reference.Path)
+		location := fmt.Sprintf("%s:%d:%s", reference.File, reference.Line, key)
+
+		if len(reference.Path) == 0 || isDefined(keys, reference.Path) || reported[location] {
+			continue
+		}
+
+		reported[location] = true
+		findings = append(findings, diagnostic.Diagnostic{
+			File:     reference.File,
+			Line:     reference.Line,
+			Column:   reference.Column,
+			Rule:     diagnostic.UndefinedValueRule,
+			Severity: diagnostic.SeverityWarning,
+			Message:  fmt.Sprintf(".Values.%s is used but not defined in %s", key, file),
+		})
+	}
+
+	return findings
+}
+
+// The default values of a chart are checked against its templates. Other values files of the chart only override some
+// of them, and values files outside of a chart have no templates to check against.
+func checkTemplateUsage(valuesData *yaml.Node, rows []document.ValueRow, file string, options document.Options) []diagnostic.Diagnostic {
+	if path.Base(file) != chartValuesFileName {
+		return []diagnostic.Diagnostic{}
+	}
+
+	references, warnings, found, err := helm.FindValuesReferences(options.FileSystem(), file)
+	if err != nil {
+		return []diagnostic.Diagnostic{{File: file, Rule: diagnostic.ErrorRule, Severity: diagnostic.SeverityError, Message: fmt.Sprintf("failed to read chart templates: %s", err)}}
+	}
+
+	if !found {
+		return []diagnostic.Diagnostic{}
+	}
+
+	findings := append(warnings, checkUnusedValues(rows, references, getSubchartKeys(options, file), file)...)
+	return append(findings, checkUndefinedValues(document.KeyPaths(valuesData), references, file)...)
+}
@@ -0,0 +1,387 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+	"github.com/theEndBeta/yaml-docs/pkg/document"
+	"github.com/theEndBeta/yaml-docs/pkg/helm"
+)
+
+// Matches a comment line up to the cursor, capturing what precedes the annotation being typed and the annotation itself
+var commentPrefixRegex = regexp.MustCompile(`^(\s*#\s*)(\S*)$`)
+
+type annotation struct {
+	label  string
+	text   string
+	detail string
+}
+
+var annotations = []annotation{
+	{label: "--", text: "-- ", detail: "Description of the key below, documented in the values table"},
+	{label: "@default", text: "@default -- ", detail: "Default shown in the values table instead of the actual value"},
+}
+
+// Server is a language server for values files, which shows what the generated documentation will say about each key
+// and reports comments that won't make it into the documentation
+type Server struct {
+	options   document.Options
+	documents map[string]string
+	writer    io.Writer
+}
+
+// NewServer creates a language server rendering rows the same way as documentation generated with options
+func NewServer(options document.Options) *Server {
+	return &Server{
+		options:   options,
+		documents: make(map[string]string),
+	}
+}
+
+// Serve handles the messages read from r, writing responses and notifications to w, until the client exits
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	reader := bufio.NewReader(r)
+	s.writer = w
+
+	for {
+		body, err := readMessage(reader)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			log.Warnf("Ignoring invalid message: %s", err)
+			continue
+		}
+
+		if req.Method == "exit" {
+			return nil
+		}
+
+		result, responseErr := s.handle(req)
+
+		// Notifications don't get a response
+		if req.ID == nil {
+			continue
+		}
+
+		if err := s.respond(req.ID, result, responseErr); err != nil {
+			return err
+		}
+	}
+}
+
+func readMessage(reader *bufio.Reader) ([]byte, error) {
+	headers, err := textproto.NewReader(reader).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	length, err := strconv.Atoi(headers.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Length header: %w", err)
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+func (s *Server) write(message interface{}) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(s.writer, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	return err
+}
+
+func (s *Server) respond(id *json.RawMessage, result interface{}, responseErr *responseError) error {
+	if responseErr != nil {
+		return s.write(response{JSONRPC: "2.0", ID: id, Error: responseErr})
+	}
+
+	encodedResult, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	return s.write(response{JSONRPC: "2.0", ID: id, Result: encodedResult})
+}
+
+func (s *Server) handle(req request) (interface{}, *responseError) {
+	switch req.Method {
+	case "initialize":
+		return initializeResult{
+			Capabilities: serverCapabilities{
+				TextDocumentSync:   fullTextDocumentSync,
+				HoverProvider:      true,
+				CompletionProvider: completionOptions{TriggerCharacters: []string{"@", "-"}},
+			},
+			ServerInfo: serverInfo{Name: "yaml-docs", Version: s.options.YamlDocsVersion},
+		}, nil
+	case "shutdown":
+		return nil, nil
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+
+		s.updateDocument(params.TextDocument.URI, params.TextDocument.Text)
+		return nil, nil
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+
+		// Documents are synced in full, so the last change holds the whole text
+		if len(params.ContentChanges) > 0 {
+			s.updateDocument(params.TextDocument.URI, params.ContentChanges[len(params.ContentChanges)-1].Text)
+		}
+
+		return nil, nil
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+
+		delete(s.documents, params.TextDocument.URI)
+		s.publishDiagnostics(params.TextDocument.URI, make([]lspDiagnostic, 0))
+		return nil, nil
+	case "textDocument/hover":
+		var params textDocumentPositionParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+
+		return s.hover(params), nil
+	case "textDocument/completion":
+		var params textDocumentPositionParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+
+		return s.complete(params), nil
+	}
+
+	return nil, &responseError{Code: methodNotFoundCode, Message: fmt.Sprintf("method %s is not supported", req.Method)}
+}
+
+func invalidParams(err error) *responseError {
+	return &responseError{Code: invalidParamsCode, Message: err.Error()}
+}
+
+func (s *Server) updateDocument(uri string, text string) {
+	s.documents[uri] = text
+	s.publishDiagnostics(uri, s.diagnose(uri, text))
+}
+
+func (s *Server) publishDiagnostics(uri string, diagnostics []lspDiagnostic) {
+	err := s.write(notification{
+		JSONRPC: "2.0",
+		Method:  publishDiagnosticsName,
+		Params:  publishDiagnosticsParams{URI: uri, Diagnostics: diagnostics},
+	})
+
+	if err != nil {
+		log.Errorf("Failed to publish diagnostics for %s: %s", uri, err)
+	}
+}
+
+func (s *Server) rows(uri string) ([]document.ValueRow, error) {
+	valuesData, _, err := helm.ParseValuesContents(uri, []byte(s.documents[uri]))
+	if err != nil {
+		return nil, err
+	}
+
+	return document.ValueRows(valuesData, s.options)
+}
+
+func (s *Server) diagnose(uri string, text string) []lspDiagnostic {
+	valuesData, diagnostics, err := helm.ParseValuesContents(uri, []byte(text))
+
+	if err != nil {
+		diagnostics = append(diagnostics, helm.DiagnosticFromError(err, diagnostic.SeverityError))
+	} else if rows, err := document.ValueRows(valuesData, s.options); err != nil {
+		diagnostics = append(diagnostics, diagnostic.Diagnostic{File: uri, Rule: diagnostic.ErrorRule, Severity: diagnostic.SeverityError, Message: err.Error()})
+	} else {
+		diagnostics = append(diagnostics, document.UndocumentedKeys(rows, uri)...)
+	}
+
+	lines := strings.Split(text, "\n")
+	converted := make([]lspDiagnostic, 0, len(diagnostics))
+
+	for _, d := range diagnostics {
+		converted = append(converted, convertDiagnostic(d, lines))
+	}
+
+	return converted
+}
+
+// Diagnostics use 1-based lines and columns counted in characters, the protocol 0-based lines and columns counted in
+// UTF-16 code units. Each diagnostic is shown from its column to the end of its line.
+func convertDiagnostic(d diagnostic.Diagnostic, lines []string) lspDiagnostic {
+	start := position{}
+	end := position{}
+
+	if d.Line > 0 && d.Line <= len(lines) {
+		line := lines[d.Line-1]
+		start = position{Line: d.Line - 1, Character: utf16Length(prefixRunes(line, d.Column-1))}
+		end = position{Line: d.Line - 1, Character: utf16Length(line)}
+	}
+
+	severity := diagnosticSeverityInformation
+	switch d.Severity {
+	case diagnostic.SeverityError:
+		severity = diagnosticSeverityError
+	case diagnostic.SeverityWarning:
+		severity = diagnosticSeverityWarning
+	}
+
+	return lspDiagnostic{
+		Range:    textRange{Start: start, End: end},
+		Severity: severity,
+		Code:     d.Rule,
+		Source:   diagnosticsSourceName,
+		Message:  d.Message,
+	}
+}
+
+func (s *Server) hover(params textDocumentPositionParams) *hover {
+	rows, err := s.rows(params.TextDocument.URI)
+	if err != nil {
+		return nil
+	}
+
+	lines := strings.Split(s.documents[params.TextDocument.URI], "\n")
+	if params.Position.Line >= len(lines) {
+		return nil
+	}
+
+	// Several keys can share a line in flow style, pick the one closest before the cursor
+	column := len([]rune(prefixUTF16(lines[params.Position.Line], params.Position.Character))) + 1
+	var hovered *document.ValueRow
+
+	for i, row := range rows {
+		if row.LineNumber != params.Position.Line+1 || row.Column > column {
+			continue
+		}
+
+		if hovered == nil || row.Column > hovered.Column {
+			hovered = &rows[i]
+		}
+	}
+
+	if hovered == nil {
+		return nil
+	}
+
+	return &hover{Contents: markupContent{Kind: markdownMarkupKind, Value: hoverText(*hovered)}}
+}
+
+func hoverText(row document.ValueRow) string {
+	text := strings.Builder{}
+	text.WriteString(fmt.Sprintf("**%s** (%s)\n\n", row.Key, row.Type))
+
+	if row.Description != "" {
+		text.WriteString(row.Description + "\n\n")
+	}
+
+	if row.LongDefault != "" {
+		text.WriteString(fmt.Sprintf("Default:\n\n```yaml\n%s\n```", row.LongDefault))
+	} else {
+		text.WriteString(fmt.Sprintf("Default: %s", row.Default))
+	}
+
+	return text.String()
+}
+
+func (s *Server) complete(params textDocumentPositionParams) []completionItem {
+	items := make([]completionItem, 0)
+
+	lines := strings.Split(s.documents[params.TextDocument.URI], "\n")
+	if params.Position.Line >= len(lines) {
+		return items
+	}
+
+	match := commentPrefixRegex.FindStringSubmatch(prefixUTF16(lines[params.Position.Line], params.Position.Character))
+	if match == nil {
+		return items
+	}
+
+	// Replace the annotation typed so far, rather than relying on the editor's idea of where words start
+	replaced := textRange{
+		Start: position{Line: params.Position.Line, Character: utf16Length(match[1])},
+		End:   params.Position,
+	}
+
+	for _, a := range annotations {
+		if !strings.HasPrefix(a.label, match[2]) {
+			continue
+		}
+
+		items = append(items, completionItem{
+			Label:            a.label,
+			Kind:             keywordCompletionKind,
+			Detail:           a.detail,
+			InsertTextFormat: plainTextInsertFormat,
+			TextEdit:         textEdit{Range: replaced, NewText: a.text},
+		})
+	}
+
+	return items
+}
+
+func utf16Length(s string) int {
+	return len(utf16.Encode([]rune(s)))
+}
+
+// Returns the start of s up to the given number of UTF-16 code units
+func prefixUTF16(s string, units int) string {
+	count := 0
+
+	for i, r := range s {
+		if count >= units {
+			return s[:i]
+		}
+
+		count += len(utf16.Encode([]rune{r}))
+	}
+
+	return s
+}
+
+func prefixRunes(s string, runes int) string {
+	if runes <= 0 {
+		return ""
+	}
+
+	if r := []rune(s); runes < len(r) {
+		return string(r[:runes])
+	}
+
+	return s
+}
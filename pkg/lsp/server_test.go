@@ -0,0 +1,215 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/theEndBeta/yaml-docs/pkg/diagnostic"
+	"github.com/theEndBeta/yaml-docs/pkg/document"
+)
+
+const valuesURI = "file:///chart/values.yaml"
+
+const valuesText = `# -- Number of replicas
+replicas: 1
+image:
+  # -- Image tag
+  # @default -- the chart appVersion
+  tag: ""
+  # @default: latest
+  pullPolicy: IfNotPresent
+# @
+`
+
+type testMessage struct {
+	ID     *int            `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *responseError  `json:"error"`
+}
+
+func frame(t *testing.T, messages ...interface{}) *bytes.Buffer {
+	input := &bytes.Buffer{}
+
+	for _, message := range messages {
+		body, err := json.Marshal(message)
+		require.NoError(t, err)
+		fmt.Fprintf(input, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	}
+
+	return input
+}
+
+func requestMessage(id int, method string, params interface{}) map[string]interface{} {
+	return map[string]interface{}{"jsonrpc": "2.0", "id": id, "method": method, "params": params}
+}
+
+func notificationMessage(method string, params interface{}) map[string]interface{} {
+	return map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params}
+}
+
+func openValues() map[string]interface{} {
+	return notificationMessage("textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": valuesURI, "languageId": "yaml", "version": 1, "text": valuesText},
+	})
+}
+
+func positionParams(line int, character int) map[string]interface{} {
+	return map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": valuesURI},
+		"position":     map[string]interface{}{"line": line, "character": character},
+	}
+}
+
+func serve(t *testing.T, messages ...interface{}) []testMessage {
+	output := &bytes.Buffer{}
+	server := NewServer(document.Options{YamlDocsVersion: "1.0.0"})
+	require.NoError(t, server.Serve(frame(t, messages...), output))
+
+	responses := make([]testMessage, 0)
+	reader := bufio.NewReader(output)
+
+	for reader.Buffered() > 0 || output.Len() > 0 {
+		body, err := readMessage(reader)
+		require.NoError(t, err)
+
+		var message testMessage
+		require.NoError(t, json.Unmarshal(body, &message))
+		responses = append(responses, message)
+	}
+
+	return responses
+}
+
+func TestInitialize(t *testing.T) {
+	responses := serve(t, requestMessage(1, "initialize", map[string]interface{}{}), notificationMessage("exit", nil))
+
+	require.Len(t, responses, 1)
+	assert.Equal(t, 1, *responses[0].ID)
+
+	var result initializeResult
+	require.NoError(t, json.Unmarshal(responses[0].Result, &result))
+	assert.True(t, result.Capabilities.HoverProvider)
+	assert.Equal(t, fullTextDocumentSync, result.Capabilities.TextDocumentSync)
+	assert.Equal(t, "1.0.0", result.ServerInfo.Version)
+}
+
+func TestUnknownMethod(t *testing.T) {
+	responses := serve(t, requestMessage(1, "textDocument/definition", map[string]interface{}{}))
+
+	require.Len(t, responses, 1)
+	require.NotNil(t, responses[0].Error)
+	assert.Equal(t, methodNotFoundCode, responses[0].Error.Code)
+}
+
+func TestShutdownResult(t *testing.T) {
+	responses := serve(t, requestMessage(1, "shutdown", nil))
+
+	require.Len(t, responses, 1)
+	assert.Equal(t, "null", string(responses[0].Result))
+	assert.Nil(t, responses[0].Error)
+}
+
+func TestPublishDiagnostics(t *testing.T) {
+	responses := serve(t, openValues())
+
+	require.Len(t, responses, 1)
+	assert.Equal(t, publishDiagnosticsName, responses[0].Method)
+
+	var params publishDiagnosticsParams
+	require.NoError(t, json.Unmarshal(responses[0].Params, &params))
+	assert.Equal(t, valuesURI, params.URI)
+
+	require.Len(t, params.Diagnostics, 2)
+	assert.Equal(t, diagnostic.MalformedDefaultRule, params.Diagnostics[0].Code)
+	assert.Equal(t, diagnosticSeverityWarning, params.Diagnostics[0].Severity)
+	assert.Equal(t, textRange{Start: position{Line: 6, Character: 2}, End: position{Line: 6, Character: 20}}, params.Diagnostics[0].Range)
+
+	assert.Equal(t, diagnostic.UndocumentedKeyRule, params.Diagnostics[1].Code)
+	assert.Equal(t, diagnosticSeverityInformation, params.Diagnostics[1].Severity)
+	assert.Equal(t, 7, params.Diagnostics[1].Range.Start.Line)
+}
+
+func TestPublishDiagnosticsInvalidYaml(t *testing.T) {
+	responses := serve(t, notificationMessage("textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": valuesURI, "text": "a: 1\nb: [\n"},
+	}))
+
+	var params publishDiagnosticsParams
+	require.NoError(t, json.Unmarshal(responses[0].Params, &params))
+	require.Len(t, params.Diagnostics, 1)
+	assert.Equal(t, diagnostic.InvalidYamlRule, params.Diagnostics[0].Code)
+	assert.Equal(t, diagnosticSeverityError, params.Diagnostics[0].Severity)
+}
+
+func TestDidChangeAndClose(t *testing.T) {
+	responses := serve(t,
+		openValues(),
+		notificationMessage("textDocument/didChange", map[string]interface{}{
+			"textDocument":   map[string]interface{}{"uri": valuesURI, "version": 2},
+			"contentChanges": []map[string]interface{}{{"text": "# -- Replicas\nreplicas: 1\n"}},
+		}),
+		notificationMessage("textDocument/didClose", map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": valuesURI},
+		}),
+	)
+
+	require.Len(t, responses, 3)
+
+	for _, response := range responses[1:] {
+		var params publishDiagnosticsParams
+		require.NoError(t, json.Unmarshal(response.Params, &params))
+		assert.Empty(t, params.Diagnostics)
+	}
+}
+
+func TestHover(t *testing.T) {
+	responses := serve(t,
+		openValues(),
+		requestMessage(1, "textDocument/hover", positionParams(1, 3)),
+		requestMessage(2, "textDocument/hover", positionParams(5, 3)),
+		requestMessage(3, "textDocument/hover", positionParams(2, 1)),
+	)
+
+	require.Len(t, responses, 4)
+
+	var replicas hover
+	require.NoError(t, json.Unmarshal(responses[1].Result, &replicas))
+	assert.Equal(t, markdownMarkupKind, replicas.Contents.Kind)
+	assert.Equal(t, "**replicas** (int)\n\nNumber of replicas\n\nDefault: `1`", replicas.Contents.Value)
+
+	var tag hover
+	require.NoError(t, json.Unmarshal(responses[2].Result, &tag))
+	assert.Equal(t, "**image.tag** (string)\n\nImage tag\n\nDefault: the chart appVersion", tag.Contents.Value)
+
+	// Objects without a description have no row of their own
+	assert.Equal(t, "null", string(responses[3].Result))
+}
+
+func TestCompletion(t *testing.T) {
+	responses := serve(t,
+		openValues(),
+		requestMessage(1, "textDocument/completion", positionParams(8, 3)),
+		requestMessage(2, "textDocument/completion", positionParams(1, 3)),
+	)
+
+	require.Len(t, responses, 3)
+
+	var items []completionItem
+	require.NoError(t, json.Unmarshal(responses[1].Result, &items))
+	require.Len(t, items, 1)
+	assert.Equal(t, "@default", items[0].Label)
+	assert.Equal(t, "@default -- ", items[0].TextEdit.NewText)
+	assert.Equal(t, textRange{Start: position{Line: 8, Character: 2}, End: position{Line: 8, Character: 3}}, items[0].TextEdit.Range)
+
+	// Completion is only offered in comments
+	require.NoError(t, json.Unmarshal(responses[2].Result, &items))
+	assert.Empty(t, items)
+}
@@ -0,0 +1,236 @@
+package preview
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+// Reloads the page whenever the server sends an event, and reconnects on its own if the server restarts
+const reloadScript = `new EventSource("/_events").onmessage = function () { location.reload(); };`
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+<style>
+body { max-width: 980px; margin: 0 auto; padding: 32px; font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; line-height: 1.5; color: #24292f; }
+table { border-collapse: collapse; display: block; overflow: auto; }
+th, td { border: 1px solid #d0d7de; padding: 6px 13px; }
+tr:nth-child(2n) { background-color: #f6f8fa; }
+code, pre { font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; background-color: #f6f8fa; border-radius: 6px; }
+code { padding: 0.2em 0.4em; }
+pre { padding: 16px; overflow: auto; }
+pre code { padding: 0; }
+.error { color: #cf222e; white-space: pre-wrap; }
+</style>
+</head>
+<body>
+{{ if .Error }}<pre class="error">{{ .Error }}</pre>{{ else }}{{ .Body }}{{ end }}
+<script>{{ .Script }}</script>
+</body>
+</html>
+`))
+
+// Links and images in the rendered documentation, which are the only files the server serves
+var referenceRegex = regexp.MustCompile(`(?:src|href)="([^"]*)"`)
+
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM, extension.Footnote),
+	goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
+)
+
+type page struct {
+	Title  string
+	Body   template.HTML
+	Error  string
+	Script template.JS
+}
+
+// Server renders the documentation to HTML on every request, and tells open pages to reload when Reload is called.
+// Files the documentation links to are served from files, so that images and links relative to the documentation
+// keep working. Nothing else is, neither directories, hidden files nor files the documentation doesn't link to.
+type Server struct {
+	title      string
+	render     func() ([]byte, error)
+	files      fs.FS
+	fileServer http.Handler
+	mutex      sync.Mutex
+	clients    map[chan struct{}]bool
+}
+
+// NewServer creates a server with the given page title, rendering the markdown returned by render
+func NewServer(title string, files fs.FS, render func() ([]byte, error)) *Server {
+	return &Server{
+		title:      title,
+		render:     render,
+		files:      files,
+		fileServer: http.FileServer(http.FS(files)),
+		clients:    make(map[chan struct{}]bool),
+	}
+}
+
+// RenderHTML converts rendered markdown documentation to HTML
+func RenderHTML(source []byte) ([]byte, error) {
+	var output bytes.Buffer
+	if err := markdown.Convert(source, &output); err != nil {
+		return nil, err
+	}
+
+	return output.Bytes(), nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/":
+		s.servePage(w)
+	case "/_events":
+		s.serveEvents(w, r)
+	default:
+		s.serveFile(w, r)
+	}
+}
+
+// Paths of the local files that rendered HTML links to, relative to the root the documentation is served at
+func getReferencedFiles(source []byte) map[string]bool {
+	referencedFiles := make(map[string]bool)
+
+	for _, match := range referenceRegex.FindAllSubmatch(source, -1) {
+		reference, err := url.Parse(html.UnescapeString(string(match[1])))
+		if err != nil || reference.Scheme != "" || reference.Host != "" || reference.Path == "" {
+			continue
+		}
+
+		referencedFiles[strings.TrimPrefix(path.Clean("/"+reference.Path), "/")] = true
+	}
+
+	return referencedFiles
+}
+
+func isHiddenPath(name string) bool {
+	for _, segment := range strings.Split(name, "/") {
+		if strings.HasPrefix(segment, ".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// The documentation is rendered to find the files it links to, as it may have changed since the page was served
+func (s *Server) isReferenced(name string) bool {
+	source, err := s.render()
+	if err != nil {
+		return false
+	}
+
+	body, err := RenderHTML(source)
+	if err != nil {
+		return false
+	}
+
+	return getReferencedFiles(body)[name]
+}
+
+func (s *Server) serveFile(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(path.Clean(r.URL.Path), "/")
+	if isHiddenPath(name) || !s.isReferenced(name) {
+		http.NotFound(w, r)
+		return
+	}
+
+	if info, err := fs.Stat(s.files, name); err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.fileServer.ServeHTTP(w, r)
+}
+
+// Reload tells every open page to reload
+func (s *Server) Reload() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for client := range s.clients {
+		select {
+		case client <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (s *Server) servePage(w http.ResponseWriter) {
+	p := page{Title: s.title, Script: template.JS(reloadScript)}
+	status := http.StatusOK
+
+	source, err := s.render()
+	if err == nil {
+		var body []byte
+		body, err = RenderHTML(source)
+		p.Body = template.HTML(body)
+	}
+
+	// Errors are shown in the page rather than failing the request, so that the page still reloads once they're fixed
+	if err != nil {
+		log.Errorf("Failed to render preview: %s", err)
+		p.Error = err.Error()
+		status = http.StatusInternalServerError
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+
+	if err := pageTemplate.Execute(w, p); err != nil {
+		log.Errorf("Failed to write preview: %s", err)
+	}
+}
+
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	client := make(chan struct{}, 1)
+
+	s.mutex.Lock()
+	s.clients[client] = true
+	s.mutex.Unlock()
+
+	defer func() {
+		s.mutex.Lock()
+		delete(s.clients, client)
+		s.mutex.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-client:
+			fmt.Fprint(w, "data: reload\n\n")
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package preview
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(render func() ([]byte, error)) *httptest.Server {
+	files := fstest.MapFS{
+		"logo.png":      {Data: []byte("png")},
+		"values.yaml":   {Data: []byte("a: 1")},
+		"docs/guide.md": {Data: []byte("guide")},
+		".git/config":   {Data: []byte("[core]")},
+		".env":          {Data: []byte("TOKEN=secret")},
+	}
+
+	return httptest.NewServer(NewServer("README.md", files, render))
+}
+
+func get(t *testing.T, url string) (int, string) {
+	response, err := http.Get(url)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	return response.StatusCode, string(body)
+}
+
+func TestRenderHTML(t *testing.T) {
+	html, err := RenderHTML([]byte("| Key | Default |\n|-----|---------|\n| a | `1` |\n\n<details><summary>x</summary></details>\n"))
+
+	require.NoError(t, err)
+	assert.Contains(t, string(html), "<table>")
+	assert.Contains(t, string(html), "<td><code>1</code></td>")
+	assert.Contains(t, string(html), "<details><summary>x</summary></details>")
+}
+
+func TestServePage(t *testing.T) {
+	server := newTestServer(func() ([]byte, error) { return []byte("## Values\n"), nil })
+	defer server.Close()
+
+	status, body := get(t, server.URL)
+
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "<title>README.md</title>")
+	assert.Contains(t, body, "<h2>Values</h2>")
+	assert.Contains(t, body, "/_events")
+}
+
+func TestServePageError(t *testing.T) {
+	server := newTestServer(func() ([]byte, error) { return nil, errors.New("bad <template>") })
+	defer server.Close()
+
+	status, body := get(t, server.URL)
+
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Contains(t, body, "bad &lt;template&gt;")
+	assert.Contains(t, body, "/_events")
+}
+
+func TestServeFiles(t *testing.T) {
+	server := newTestServer(func() ([]byte, error) {
+		return []byte("![logo](logo.png) [guide](./docs/guide.md#setup) [docs](docs/) [git](.git/config) [env](/.env)\n"), nil
+	})
+	defer server.Close()
+
+	status, body := get(t, server.URL+"/logo.png")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "png", body)
+
+	status, body = get(t, server.URL+"/docs/guide.md")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "guide", body)
+
+	// Files that aren't linked, directories and hidden files are never served, even when linked
+	for _, path := range []string{"/values.yaml", "/docs/", "/.git/config", "/.env"} {
+		status, _ = get(t, server.URL+path)
+		assert.Equal(t, http.StatusNotFound, status, path)
+	}
+}
+
+func TestReload(t *testing.T) {
+	previewServer := NewServer("README.md", fstest.MapFS{}, func() ([]byte, error) { return nil, nil })
+	server := httptest.NewServer(previewServer)
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/_events")
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	assert.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(response.Body)
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, ": connected", strings.TrimSpace(line))
+
+	previewServer.Reload()
+
+	for strings.TrimSpace(line) != "data: reload" {
+		line, err = reader.ReadString('\n')
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,131 @@
+package util
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+type osFileSystem struct{}
+
+// OSFileSystem is an fs.FS reading from the host filesystem. Unlike os.DirFS it isn't rooted in a directory, and accepts
+// any path the os package does, including absolute paths and paths relative to the working directory
+var OSFileSystem fs.FS = osFileSystem{}
+
+func (osFileSystem) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+func (osFileSystem) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+func (osFileSystem) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+// GitPathPrefix marks paths that are read from a revision of a git repository, as git:<revision>:<path>
+const GitPathPrefix = "git:"
+
+type gitFileSystem struct {
+	base fs.FS
+}
+
+// NewGitFileSystem returns an fs.FS which reads paths of the form git:<revision>:<path> from the object store of the
+// git repository containing the path, and every other path from base. Paths read from git are relative to the working
+// directory, as with any other path.
+func NewGitFileSystem(base fs.FS) fs.FS {
+	return gitFileSystem{base: base}
+}
+
+// IsHostFileSystem reports whether fsys reads from the host filesystem, as OSFileSystem does or a git filesystem based on
+// it
+func IsHostFileSystem(fsys fs.FS) bool {
+	if g, ok := fsys.(gitFileSystem); ok {
+		fsys = g.base
+	}
+
+	return fsys == OSFileSystem
+}
+
+// ParseGitPath splits a path of the form git:<revision>:<path> into its revision and path
+func ParseGitPath(name string) (string, string, bool) {
+	if !strings.HasPrefix(name, GitPathPrefix) {
+		return "", "", false
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(name, GitPathPrefix), ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+
+	return parts[0], parts[1], true
+}
+
+func (g gitFileSystem) ReadFile(name string) ([]byte, error) {
+	revision, path, ok := ParseGitPath(name)
+	if !ok {
+		return fs.ReadFile(g.base, name)
+	}
+
+	contents, err := ReadGitFile(revision, path)
+	if err != nil {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: err}
+	}
+
+	return contents, nil
+}
+
+func (g gitFileSystem) Open(name string) (fs.File, error) {
+	if _, _, ok := ParseGitPath(name); !ok {
+		return g.base.Open(name)
+	}
+
+	contents, err := g.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gitFile{Reader: bytes.NewReader(contents), info: gitFileInfo{name: path.Base(name), size: int64(len(contents))}}, nil
+}
+
+func (g gitFileSystem) Stat(name string) (fs.FileInfo, error) {
+	if _, _, ok := ParseGitPath(name); !ok {
+		return fs.Stat(g.base, name)
+	}
+
+	file, err := g.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return file.Stat()
+}
+
+type gitFile struct {
+	*bytes.Reader
+	info gitFileInfo
+}
+
+func (f *gitFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *gitFile) Close() error {
+	return nil
+}
+
+type gitFileInfo struct {
+	name string
+	size int64
+}
+
+func (i gitFileInfo) Name() string       { return i.name }
+func (i gitFileInfo) Size() int64        { return i.size }
+func (i gitFileInfo) Mode() fs.FileMode  { return 0444 }
+func (i gitFileInfo) ModTime() time.Time { return time.Time{} }
+func (i gitFileInfo) IsDir() bool        { return false }
+func (i gitFileInfo) Sys() interface{}   { return nil }
@@ -0,0 +1,56 @@
+package util
+
+import (
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGitPath(t *testing.T) {
+	revision, path, ok := ParseGitPath("git:v1.4.0:charts/app/values.yaml")
+	assert.True(t, ok)
+	assert.Equal(t, "v1.4.0", revision)
+	assert.Equal(t, "charts/app/values.yaml", path)
+
+	for _, name := range []string{"values.yaml", "git:values.yaml", "git::values.yaml", "git:HEAD:"} {
+		_, _, ok := ParseGitPath(name)
+		assert.False(t, ok, name)
+	}
+}
+
+func TestGitFileSystem(t *testing.T) {
+	dir := newTestRepository(t)
+	gitPath := "git:v1:" + filepath.Join(dir, "chart", "values.yaml")
+	fsys := NewGitFileSystem(fstest.MapFS{"values.yaml": {Data: []byte("version: local\n")}})
+
+	contents, err := fs.ReadFile(fsys, gitPath)
+	require.NoError(t, err)
+	assert.Equal(t, "version: v1\n", string(contents))
+
+	info, err := fs.Stat(fsys, gitPath)
+	require.NoError(t, err)
+	assert.Equal(t, "values.yaml", info.Name())
+	assert.Equal(t, int64(12), info.Size())
+
+	file, err := fsys.Open(gitPath)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	contents, err = fs.ReadFile(fsys, "values.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "version: local\n", string(contents))
+
+	_, err = fs.ReadFile(fsys, "git:v3:"+filepath.Join(dir, "chart", "values.yaml"))
+	assert.Error(t, err)
+}
+
+func TestIsHostFileSystem(t *testing.T) {
+	assert.True(t, IsHostFileSystem(OSFileSystem))
+	assert.True(t, IsHostFileSystem(NewGitFileSystem(OSFileSystem)))
+	assert.False(t, IsHostFileSystem(fstest.MapFS{}))
+	assert.False(t, IsHostFileSystem(NewGitFileSystem(fstest.MapFS{})))
+}
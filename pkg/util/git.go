@@ -1,7 +1,12 @@
 package util
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 )
 
@@ -14,3 +19,109 @@ func FindGitRepositoryRoot() (string, error) {
 
 	return strings.TrimSpace(string(path)), nil
 }
+
+// Finds the root of the git repository containing path, and the path relative to that root. Directories of the path
+// that are missing from the working tree, such as those removed since an older revision, are skipped when looking for
+// the repository.
+func getGitRepositoryPath(path string) (string, string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", "", err
+	}
+
+	dir, rest := filepath.Dir(absPath), filepath.Base(absPath)
+	for {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			break
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", fmt.Errorf("no directory of %s exists", path)
+		}
+
+		dir, rest = parent, filepath.Join(filepath.Base(dir), rest)
+	}
+
+	// git reports the root with symlinks resolved
+	dir, err = filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	command := exec.Command("git", "rev-parse", "--show-toplevel")
+	command.Dir = dir
+
+	output, err := command.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", "", fmt.Errorf("failed to find the git repository of %s: %s", path, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+
+		return "", "", fmt.Errorf("failed to find the git repository of %s: %w", path, err)
+	}
+
+	root := strings.TrimSpace(string(output))
+	relativeDir, err := filepath.Rel(root, dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	return root, filepath.ToSlash(filepath.Join(relativeDir, rest)), nil
+}
+
+// ReadGitFile reads a file as it was at a revision of the git repository containing it. The path is relative to the
+// working directory, as for any other file, rather than to the root of the repository. The error wraps fs.ErrNotExist
+// if the revision exists but doesn't contain the file.
+func ReadGitFile(revision string, path string) ([]byte, error) {
+	root, repositoryPath, err := getGitRepositoryPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s at revision %s: %w", path, revision, err)
+	}
+
+	command := exec.Command("git", "cat-file", "blob", fmt.Sprintf("%s:%s", revision, repositoryPath))
+	command.Dir = root
+
+	contents, err := command.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && gitRevisionExists(root, revision) {
+			return nil, fmt.Errorf("%s does not exist at revision %s: %w", path, revision, fs.ErrNotExist)
+		}
+
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("failed to read %s at revision %s: %s", path, revision, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+
+		return nil, fmt.Errorf("failed to read %s at revision %s: %w", path, revision, err)
+	}
+
+	return contents, nil
+}
+
+func gitRevisionExists(dir string, revision string) bool {
+	command := exec.Command("git", "rev-parse", "--verify", "--quiet", revision+"^{commit}")
+	command.Dir = dir
+
+	return command.Run() == nil
+}
+
+// ListGitTags returns the tags reachable from revision in the git repository containing dir, from the lowest version to
+// the highest
+func ListGitTags(dir string, revision string) ([]string, error) {
+	command := exec.Command("git", "tag", "--list", "--merged", revision, "--sort=version:refname")
+	command.Dir = dir
+
+	output, err := command.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("failed to list git tags: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+
+		return nil, fmt.Errorf("failed to list git tags: %w", err)
+	}
+
+	return strings.Fields(string(output)), nil
+}
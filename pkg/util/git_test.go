@@ -0,0 +1,82 @@
+package util
+
+import (
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runGit(t *testing.T, dir string, args ...string) {
+	command := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	command.Dir = dir
+
+	output, err := command.CombinedOutput()
+	require.NoError(t, err, string(output))
+}
+
+// Creates a repository with a values file in a subdirectory, committed and tagged twice
+func newTestRepository(t *testing.T) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	chartDir := filepath.Join(dir, "chart")
+	require.NoError(t, os.Mkdir(chartDir, 0755))
+
+	runGit(t, dir, "init", "-q")
+
+	for _, version := range []string{"v1", "v2"} {
+		require.NoError(t, os.WriteFile(filepath.Join(chartDir, "values.yaml"), []byte("version: "+version+"\n"), 0644))
+		runGit(t, dir, "add", "-A")
+		runGit(t, dir, "commit", "-q", "-m", version)
+		runGit(t, dir, "tag", version)
+	}
+
+	return dir
+}
+
+func TestReadGitFile(t *testing.T) {
+	dir := newTestRepository(t)
+
+	contents, err := ReadGitFile("v1", filepath.Join(dir, "chart", "values.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "version: v1\n", string(contents))
+
+	contents, err = ReadGitFile("HEAD", filepath.Join(dir, "chart", "values.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "version: v2\n", string(contents))
+
+	_, err = ReadGitFile("v1", filepath.Join(dir, "chart", "missing.yaml"))
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	_, err = ReadGitFile("v3", filepath.Join(dir, "chart", "values.yaml"))
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestReadGitFileRemovedDirectory(t *testing.T) {
+	dir := newTestRepository(t)
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "chart")))
+
+	contents, err := ReadGitFile("v1", filepath.Join(dir, "chart", "values.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "version: v1\n", string(contents))
+}
+
+func TestListGitTags(t *testing.T) {
+	dir := newTestRepository(t)
+
+	tags, err := ListGitTags(filepath.Join(dir, "chart"), "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1", "v2"}, tags)
+
+	tags, err = ListGitTags(filepath.Join(dir, "chart"), "v1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1"}, tags)
+}
@@ -0,0 +1,96 @@
+package util
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	log "github.com/sirupsen/logrus"
+)
+
+// WatchFiles calls onChange with the files that changed, once no further changes happened for the debounce duration. The
+// directories containing the files are watched rather than the files themselves, so that files replaced by editors on
+// save, or created after watching started, are still picked up. Blocks until the context is done.
+func WatchFiles(ctx context.Context, files []string, debounce time.Duration, onChange func(changed []string)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	defer watcher.Close()
+
+	watchedFiles := make(map[string]string)
+	watchedDirs := make(map[string]bool)
+
+	for _, file := range files {
+		absolutePath, err := filepath.Abs(file)
+		if err != nil {
+			return err
+		}
+
+		watchedFiles[absolutePath] = file
+		watchedDirs[filepath.Dir(absolutePath)] = true
+	}
+
+	for dir := range watchedDirs {
+		if err := watcher.Add(dir); err != nil {
+			return err
+		}
+	}
+
+	newDebounceTimer := func() <-chan time.Time { return time.After(debounce) }
+	return debounceEvents(ctx, watcher.Events, watcher.Errors, watchedFiles, newDebounceTimer, onChange)
+}
+
+// Events are debounced with a new timer for every change, earlier timers are simply no longer waited on. Timers are
+// created by newDebounceTimer, so that tests don't depend on the wall clock.
+func debounceEvents(
+	ctx context.Context,
+	events <-chan fsnotify.Event,
+	errors <-chan error,
+	watchedFiles map[string]string,
+	newDebounceTimer func() <-chan time.Time,
+	onChange func(changed []string),
+) error {
+	pending := make(map[string]bool)
+	var debounceC <-chan time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+
+			absolutePath, _ := filepath.Abs(event.Name)
+			file, isWatched := watchedFiles[absolutePath]
+			if !isWatched || event.Op == fsnotify.Chmod {
+				continue
+			}
+
+			pending[file] = true
+			debounceC = newDebounceTimer()
+		case err, ok := <-errors:
+			if !ok {
+				return nil
+			}
+
+			log.Warnf("Error watching files: %s", err)
+		case <-debounceC:
+			changed := make([]string, 0, len(pending))
+			for file := range pending {
+				changed = append(changed, file)
+			}
+
+			sort.Strings(changed)
+			pending = make(map[string]bool)
+			debounceC = nil
+
+			onChange(changed)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package util
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebounceEvents(t *testing.T) {
+	valuesFile, err := filepath.Abs("values.yaml")
+	require.NoError(t, err)
+
+	events := make(chan fsnotify.Event)
+	timers := make(chan chan time.Time, 10)
+	changes := make(chan []string, 10)
+	done := make(chan error)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	newDebounceTimer := func() <-chan time.Time {
+		timer := make(chan time.Time, 1)
+		timers <- timer
+		return timer
+	}
+
+	go func() {
+		done <- debounceEvents(ctx, events, make(chan error), map[string]string{valuesFile: "values.yaml"}, newDebounceTimer, func(changed []string) {
+			changes <- changed
+		})
+	}()
+
+	// Every watched change restarts the debounce, changes to other files and permissions are ignored
+	events <- fsnotify.Event{Name: "README.md", Op: fsnotify.Write}
+	events <- fsnotify.Event{Name: valuesFile, Op: fsnotify.Chmod}
+	var timer chan time.Time
+	for i := 0; i < 3; i++ {
+		events <- fsnotify.Event{Name: valuesFile, Op: fsnotify.Write}
+		timer = <-timers
+	}
+
+	assert.Empty(t, timers)
+
+	timer <- time.Time{}
+	assert.Equal(t, []string{"values.yaml"}, <-changes)
+
+	cancel()
+	require.NoError(t, <-done)
+	assert.Empty(t, changes)
+}